@@ -0,0 +1,259 @@
+// Package belief works out, from one player's point of view, how likely every
+// card in a game is to be each role, given what that player has seen happen.
+//
+// Cards are named as they would be chosen as targets: players by name and
+// table cards as #0, #1, #2...
+package belief
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+// How close every role's total probability must come to its number of copies
+// before Beliefs stops rebalancing, and how many tries it gets to come that close.
+const (
+	tolerance     = 1e-9
+	maxBalancings = 200
+)
+
+// Beliefs holds, for every card, the probability of each role being that card.
+// Every card is certainly some role, and every role is on as many cards as it
+// has copies, so rows and columns always add up; each thing seen is taken into
+// account by rebalancing the others to match.
+type Beliefs struct {
+	cards  []string
+	index  map[string]int
+	roles  []role.Role
+	copies []float64
+	p      [][]float64
+}
+
+// New starts out knowing nothing, with every card equally likely to be any copy in deck.
+// There must be as many copies in the deck as there are cards.
+func New(cards []string, deck map[role.Role]int) (*Beliefs, error) {
+	total := 0
+	for _, n := range deck {
+		total += n
+	}
+	if total != len(cards) {
+		return nil, fmt.Errorf("There are %d cards but %d roles in the deck", len(cards), total)
+	}
+
+	b := &Beliefs{
+		cards: append([]string(nil), cards...),
+		index: make(map[string]int, len(cards)),
+		roles: make([]role.Role, 0, len(deck)),
+	}
+	for i, card := range cards {
+		if _, ok := b.index[card]; ok {
+			return nil, fmt.Errorf("There are two cards named %s", card)
+		}
+		b.index[card] = i
+	}
+	for r := range deck {
+		b.roles = append(b.roles, r)
+	}
+	sort.Slice(b.roles, func(i, j int) bool { return b.roles[i] < b.roles[j] })
+
+	b.copies = make([]float64, len(b.roles))
+	for j, r := range b.roles {
+		b.copies[j] = float64(deck[r])
+	}
+	b.p = make([][]float64, len(cards))
+	for i := range b.p {
+		b.p[i] = make([]float64, len(b.roles))
+		for j := range b.roles {
+			b.p[i][j] = b.copies[j] / float64(total)
+		}
+	}
+	return b, nil
+}
+
+// FromView starts out knowing nothing of the cards in the game seen in v.
+func FromView(v game.View) (*Beliefs, error) {
+	cards := make([]string, 0, len(v.Players)+len(v.TableCards))
+	for _, p := range v.Players {
+		cards = append(cards, p.Name)
+	}
+	cards = append(cards, v.TableCards...)
+	return New(cards, v.Deck)
+}
+
+// Cards lists the cards, players first in seating order, then table cards.
+func (b *Beliefs) Cards() []string {
+	return append([]string(nil), b.cards...)
+}
+
+// Shown takes into account that card was seen to be r.
+func (b *Beliefs) Shown(card string, r role.Role) error {
+	i, ok := b.index[card]
+	if !ok {
+		return fmt.Errorf("No card %s", card)
+	}
+	j, ok := b.roleIndex(r)
+	if !ok {
+		return fmt.Errorf("The %s is not in this game", r)
+	}
+
+	for k := range b.p[i] {
+		b.p[i][k] = 0
+	}
+	b.p[i][j] = 1
+	b.balance()
+	return nil
+}
+
+// Swapped takes into account that the cards a and c were exchanged with the given chance,
+// which is 1 if the swap surely happened and 0.5 if there is no telling whether it did.
+func (b *Beliefs) Swapped(a, c string, chance float64) error {
+	i, ok := b.index[a]
+	if !ok {
+		return fmt.Errorf("No card %s", a)
+	}
+	k, ok := b.index[c]
+	if !ok {
+		return fmt.Errorf("No card %s", c)
+	}
+
+	for j := range b.roles {
+		pa, pc := b.p[i][j], b.p[k][j]
+		b.p[i][j] = (1-chance)*pa + chance*pc
+		b.p[k][j] = (1-chance)*pc + chance*pa
+	}
+	return nil
+}
+
+// Chance gives how likely card is to be r.
+func (b *Beliefs) Chance(card string, r role.Role) float64 {
+	i, ok := b.index[card]
+	if !ok {
+		return 0
+	}
+	j, ok := b.roleIndex(r)
+	if !ok {
+		return 0
+	}
+	return b.p[i][j]
+}
+
+// Distribution gives how likely card is to be each role in the game.
+func (b *Beliefs) Distribution(card string) map[role.Role]float64 {
+	i, ok := b.index[card]
+	if !ok {
+		return nil
+	}
+	dist := make(map[role.Role]float64, len(b.roles))
+	for j, r := range b.roles {
+		dist[r] = b.p[i][j]
+	}
+	return dist
+}
+
+// Likeliest gives the role card is most likely to be, and how likely that is.
+func (b *Beliefs) Likeliest(card string) (role.Role, float64) {
+	best, bestChance := role.NoSuchRole, 0.0
+	if i, ok := b.index[card]; ok {
+		for j, r := range b.roles {
+			if b.p[i][j] > bestChance {
+				best, bestChance = r, b.p[i][j]
+			}
+		}
+	}
+	return best, bestChance
+}
+
+// Expected averages value over what card might be.
+func (b *Beliefs) Expected(card string, value func(role.Role) float64) float64 {
+	total := 0.0
+	if i, ok := b.index[card]; ok {
+		for j, r := range b.roles {
+			total += b.p[i][j] * value(r)
+		}
+	}
+	return total
+}
+
+func (b *Beliefs) roleIndex(r role.Role) (int, bool) {
+	for j, candidate := range b.roles {
+		if candidate == r {
+			return j, true
+		}
+	}
+	return 0, false
+}
+
+// balance scales roles to their number of copies and cards to certainty in
+// turn until both add up. Cards known for certain are left alone, and their
+// roles taken out of what is left for the others.
+func (b *Beliefs) balance() {
+	known := make([]bool, len(b.cards))
+	left := append([]float64(nil), b.copies...)
+	for i := range b.cards {
+		for j, chance := range b.p[i] {
+			if chance == 1 {
+				known[i] = true
+				left[j]--
+			}
+		}
+	}
+
+	for n := 0; n < maxBalancings; n++ {
+		for j := range b.roles {
+			sum := 0.0
+			for i := range b.cards {
+				if !known[i] {
+					sum += b.p[i][j]
+				}
+			}
+			if sum > 0 {
+				scale := left[j] / sum
+				if scale < 0 {
+					scale = 0
+				}
+				for i := range b.cards {
+					if !known[i] {
+						b.p[i][j] *= scale
+					}
+				}
+			}
+		}
+
+		for i := range b.cards {
+			sum := 0.0
+			for _, chance := range b.p[i] {
+				sum += chance
+			}
+			if sum > 0 && !known[i] {
+				for j := range b.p[i] {
+					b.p[i][j] /= sum
+				}
+			}
+		}
+
+		if b.balanced(known, left) {
+			return
+		}
+	}
+}
+
+func (b *Beliefs) balanced(known []bool, left []float64) bool {
+	for j := range b.roles {
+		sum := 0.0
+		for i := range b.cards {
+			if !known[i] {
+				sum += b.p[i][j]
+			}
+		}
+		if left[j] < 0 {
+			continue
+		}
+		if diff := sum - left[j]; diff > tolerance || diff < -tolerance {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+package belief
+
+import (
+	"math"
+	"testing"
+
+	"github.com/petertseng/mascarade/role"
+)
+
+// Close enough to count as the same chance, as Beliefs may give up
+// rebalancing before its sums come within tolerance.
+const near = 1e-3
+
+var cards = []string{"Alice", "Bob", "Carol", "#0"}
+
+type seen struct {
+	// Shown if r is given, otherwise Swapped.
+	card   string
+	r      role.Role
+	other  string
+	chance float64
+}
+
+func TestMarginals(t *testing.T) {
+	deck := map[role.Role]int{role.King: 1, role.Queen: 1, role.Judge: 1, role.Bishop: 1}
+	tests := []struct {
+		desc   string
+		events []seen
+		want   map[string]map[role.Role]float64
+	}{
+		{
+			"nothing seen",
+			nil,
+			map[string]map[role.Role]float64{"Alice": {role.King: 0.25}, "#0": {role.Bishop: 0.25}},
+		},
+		{
+			"one shown",
+			[]seen{{card: "Alice", r: role.King}},
+			map[string]map[role.Role]float64{"Alice": {role.King: 1, role.Queen: 0}, "Bob": {role.King: 0, role.Queen: 1.0 / 3}},
+		},
+		{
+			"two shown and surely swapped",
+			[]seen{{card: "Alice", r: role.King}, {card: "Bob", r: role.Queen}, {card: "Alice", other: "Bob", chance: 1}},
+			map[string]map[role.Role]float64{"Alice": {role.Queen: 1}, "Bob": {role.King: 1}, "Carol": {role.Judge: 0.5}},
+		},
+		{
+			"two shown and maybe swapped",
+			[]seen{{card: "Alice", r: role.King}, {card: "Bob", r: role.Queen}, {card: "Alice", other: "Bob", chance: 0.5}},
+			map[string]map[role.Role]float64{"Alice": {role.King: 0.5, role.Queen: 0.5}, "Bob": {role.King: 0.5, role.Queen: 0.5}, "#0": {role.King: 0}},
+		},
+		{
+			"shown, then maybe swapped with the unknown",
+			[]seen{{card: "Alice", r: role.King}, {card: "Alice", other: "#0", chance: 0.5}},
+			map[string]map[role.Role]float64{"Alice": {role.King: 0.5, role.Queen: 1.0 / 6}, "#0": {role.King: 0.5}, "Bob": {role.King: 0}},
+		},
+		{
+			"swapped back",
+			[]seen{{card: "Alice", r: role.King}, {card: "Alice", other: "Bob", chance: 1}, {card: "Bob", other: "Alice", chance: 1}},
+			map[string]map[role.Role]float64{"Alice": {role.King: 1}, "Bob": {role.King: 0}},
+		},
+	}
+
+	for _, test := range tests {
+		b, err := New(cards, deck)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range test.events {
+			if e.other == "" {
+				err = b.Shown(e.card, e.r)
+			} else {
+				err = b.Swapped(e.card, e.other, e.chance)
+			}
+			if err != nil {
+				t.Fatalf("%s: %s", test.desc, err)
+			}
+		}
+
+		for card, chances := range test.want {
+			for r, want := range chances {
+				if got := b.Chance(card, r); math.Abs(got-want) > near {
+					t.Errorf("%s: %s is the %s with chance %.3f, want %.3f", test.desc, card, r, got, want)
+				}
+			}
+		}
+		checkSums(t, test.desc, b, deck)
+	}
+}
+
+func TestPairMarginals(t *testing.T) {
+	deck := map[role.Role]int{role.Peasant: 2, role.King: 1, role.Queen: 1}
+	b, err := New(cards, deck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Chance("Alice", role.Peasant); math.Abs(got-0.5) > near {
+		t.Errorf("Alice is a Peasant with chance %.3f, want 0.5", got)
+	}
+
+	if err := b.Shown("Alice", role.Peasant); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Chance("Bob", role.Peasant); math.Abs(got-1.0/3) > near {
+		t.Errorf("Bob is the other Peasant with chance %.3f, want 1/3", got)
+	}
+	checkSums(t, "pair", b, deck)
+}
+
+func TestBadEvents(t *testing.T) {
+	b, err := New(cards, map[role.Role]int{role.King: 1, role.Queen: 1, role.Judge: 1, role.Bishop: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Shown("Dave", role.King); err == nil {
+		t.Error("Showed a card that isn't in the game")
+	}
+	if err := b.Shown("Alice", role.Witch); err == nil {
+		t.Error("Showed a role that isn't in the game")
+	}
+	if err := b.Swapped("Alice", "Dave", 1); err == nil {
+		t.Error("Swapped with a card that isn't in the game")
+	}
+	if _, err := New(cards, map[role.Role]int{role.King: 1}); err == nil {
+		t.Error("Made beliefs about 4 cards from a deck of 1")
+	}
+}
+
+// checkSums makes sure every card is certainly some role, and every role is
+// on as many cards as it has copies.
+func checkSums(t *testing.T, desc string, b *Beliefs, deck map[role.Role]int) {
+	t.Helper()
+	roleSums := make(map[role.Role]float64)
+	for _, card := range b.Cards() {
+		sum := 0.0
+		for r, chance := range b.Distribution(card) {
+			sum += chance
+			roleSums[r] += chance
+		}
+		if math.Abs(sum-1) > near {
+			t.Errorf("%s: %s's chances add up to %.3f", desc, card, sum)
+		}
+	}
+	for r, copies := range deck {
+		if math.Abs(roleSums[r]-float64(copies)) > near {
+			t.Errorf("%s: the %s's chances add up to %.3f, want %d", desc, r, roleSums[r], copies)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package belief
+
+import (
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+// An event is something a player saw that bears on where the cards are:
+// either two cards that may have been swapped, or a card shown to be a role.
+type event struct {
+	a, b   string
+	chance float64
+	shown  role.Role
+}
+
+// A Tracker keeps the history of every swap (or not) and every card one
+// player saw, as they are told of them, so that it can tell what that player
+// should believe. Until it is asked, it doesn't need to know what cards there are.
+type Tracker struct {
+	history []event
+
+	// What the history up to applied comes to, once asked for.
+	beliefs *Beliefs
+	applied int
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// Swapped records that the cards a and c were exchanged with the given chance,
+// as Beliefs.Swapped takes it.
+func (t *Tracker) Swapped(a, c string, chance float64) {
+	t.history = append(t.history, event{a: a, b: c, chance: chance})
+}
+
+// Shown records that card was seen to be r.
+func (t *Tracker) Shown(card string, r role.Role) {
+	t.history = append(t.history, event{a: card, shown: r})
+}
+
+// Beliefs takes into account everything recorded since it was last asked, over
+// the game seen in v. The Beliefs are the tracker's own, and change as it
+// takes more into account. Something recorded that can't be taken into
+// account, such as a card not in the game, is left out once its error is returned.
+func (t *Tracker) Beliefs(v game.View) (*Beliefs, error) {
+	if t.beliefs == nil {
+		b, err := FromView(v)
+		if err != nil {
+			return nil, err
+		}
+		t.beliefs = b
+	}
+
+	for t.applied < len(t.history) {
+		e := t.history[t.applied]
+		t.applied++
+		var err error
+		if e.b == "" {
+			err = t.beliefs.Shown(e.a, e.shown)
+		} else {
+			err = t.beliefs.Swapped(e.a, e.b, e.chance)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t.beliefs, nil
+}
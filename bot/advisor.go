@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/petertseng/mascarade/format"
+	"github.com/petertseng/mascarade/game"
+)
+
+// Advice is a move suggested to a player, with the reason for it.
+type Advice struct {
+	Action game.Action
+	Reason string
+}
+
+func (a Advice) String() string {
+	return fmt.Sprintf("%s (%s)", a.Action, a.Reason)
+}
+
+func percent(chance float64) string {
+	return fmt.Sprintf("%.0f%%", chance*100)
+}
+
+// An Advisor follows a game on behalf of every player, remembering what
+// each of them has seen, so that it can suggest moves to any of them as a
+// Knowing bot would make them.
+type Advisor struct {
+	table   *Table
+	players map[string]*Knowing
+}
+
+// NewAdvisor makes an advisor for the named players.
+// The game must be made with its Formatter for it to learn anything.
+func NewAdvisor(rng *rand.Rand, names []string) *Advisor {
+	players := make(map[string]*Knowing, len(names))
+	seats := make(map[string]Bot, len(names))
+	for _, name := range names {
+		players[name] = NewKnowing(rng)
+		seats[name] = players[name]
+	}
+	return &Advisor{table: NewTable(rng, seats), players: players}
+}
+
+// Formatter wraps f so that the advisor learns what every player sees.
+func (a *Advisor) Formatter(f format.Formatter) format.Formatter {
+	return a.table.Formatter(f)
+}
+
+// SwapOrNot makes the active player's swap, letting the advisor know
+// whether it really happened, which only that player knows.
+func (a *Advisor) SwapOrNot(g *game.Game, target string, actuallySwap bool) error {
+	k, ok := a.players[g.ActivePlayerName()]
+	if ok {
+		k.ownSwap = &actuallySwap
+	}
+	err := g.SwapOrNot(target, actuallySwap)
+	if err != nil && ok {
+		k.ownSwap = nil
+	}
+	return err
+}
+
+// Answer lets the advisor know how a player answered a prompt. Only a
+// Fool's or Spy's choice of whether to swap needs telling, since only
+// that player knows it.
+func (a *Advisor) Answer(p game.Prompt, answer []string) {
+	k, ok := a.players[p.Player]
+	if !ok || p.Kind != game.ChooseBoolean || len(answer) != 1 {
+		return
+	}
+	if actuallySwap, err := game.ParseBool(answer[0]); err == nil {
+		k.ownSwap = &actuallySwap
+	}
+}
+
+// Advise suggests a move to the player whose view it is.
+func (a *Advisor) Advise(v game.View) (Advice, error) {
+	k, ok := a.players[v.Self]
+	if !ok {
+		return Advice{}, fmt.Errorf("Not advising %s", v.Self)
+	}
+	return k.Advise(v)
+}
@@ -0,0 +1,335 @@
+// Package bot has computer players that can fill seats at a game.
+//
+// Seat them with a Table, give its Choose method to the GameBuilder's
+// SetChoiceGetter so that they can answer prompts, make the game with the
+// Table's Formatter so that those who remember what they see are told of
+// it, and then Play the game. Kinds of bot are made by name with New, for
+// simulations and tournaments, and an Advisor suggests moves to people.
+package bot
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/petertseng/mascarade/format"
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+// A Bot decides what a player does.
+// A Bot only ever sees a game through the View of the player it is seated as.
+type Bot interface {
+	// Act chooses a move when it is the bot's turn, or when the bot must
+	// decide whether to challenge someone's claim.
+	Act(v game.View) game.Action
+	// Answer chooses the words to answer a prompt made while a power is being used.
+	Answer(v game.View, p game.Prompt) []string
+}
+
+// How many playouts a Searching bot made by New tries for every move.
+const defaultIterations = 200
+
+// A Maker makes a new bot of some kind, to play one game.
+type Maker func(rng *rand.Rand) Bot
+
+var makers = map[string]Maker{
+	"random":    func(rng *rand.Rand) Bot { return NewRandom(rng) },
+	"knowing":   func(rng *rand.Rand) Bot { return NewKnowing(rng) },
+	"searching": func(rng *rand.Rand) Bot { return NewSearching(rng, defaultIterations) },
+}
+
+// Register makes a kind of bot known to New, for simulations and tournaments.
+// It is meant to be called from init functions, and panics if the kind is already known.
+func Register(kind string, maker Maker) {
+	if _, ok := makers[kind]; ok {
+		panic(fmt.Sprintf("Bot %s is already registered", kind))
+	}
+	makers[kind] = maker
+}
+
+// New makes a bot by the name of its kind.
+func New(kind string, rng *rand.Rand) (Bot, error) {
+	maker, ok := makers[kind]
+	if !ok {
+		return nil, fmt.Errorf("No such bot %s; choose from %v", kind, Kinds())
+	}
+	return maker(rng), nil
+}
+
+// Registered tells whether New knows of a kind.
+func Registered(kind string) bool {
+	_, ok := makers[kind]
+	return ok
+}
+
+// Kinds lists the names New knows of, in order.
+func Kinds() []string {
+	kinds := make([]string, 0, len(makers))
+	for kind := range makers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Opponents lists the kinds worth playing against by default, in order:
+// every kind but searching, which so far plays worse than knowing does
+// and takes far longer about it.
+func Opponents() []string {
+	opponents := make([]string, 0, len(makers))
+	for _, kind := range Kinds() {
+		if kind != "searching" {
+			opponents = append(opponents, kind)
+		}
+	}
+	return opponents
+}
+
+// LoadExtras registers the kinds of bot that are read from files, for the
+// programs that take them: the "trained" bot, if policyPath is given, and
+// every personality in the profile at personalitiesPath, if that is given,
+// under its own name.
+func LoadExtras(policyPath, personalitiesPath string) error {
+	if policyPath != "" {
+		p, err := LoadPolicy(policyPath)
+		if err != nil {
+			return err
+		}
+		Register("trained", func(rng *rand.Rand) Bot { return NewTrained(rng, p) })
+	}
+
+	if personalitiesPath != "" {
+		profile, err := LoadPersonalities(personalitiesPath)
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(profile))
+		for name := range profile {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if Registered(name) {
+				return fmt.Errorf("Personality %s has the name of a bot", name)
+			}
+			personality := profile[name]
+			Register(name, func(rng *rand.Rand) Bot { return NewPersonal(rng, personality) })
+		}
+	}
+	return nil
+}
+
+// An Observer is a Bot that wants to be told about cards moving and being seen.
+// Cards are named as they would be chosen as targets, so table cards are #0, #1, #2...
+type Observer interface {
+	// Swapped tells that the cards of a and b may have been exchanged.
+	Swapped(a, b string)
+	// Shown tells that the bot's player saw that whose card is r.
+	Shown(whose string, r role.Role)
+}
+
+// A Refusable Bot is told when the game refuses a move or an answer it chose,
+// and why, before it is asked again.
+type Refusable interface {
+	Refused(err error)
+}
+
+// How many moves and answers a bot may have refused while one move is made
+// before the Table makes random ones for it.
+const maxRefusals = 3
+
+// A Searcher is a Bot that looks ahead by playing out copies of the game,
+// so it is told which game it is seated at. It must make those copies with
+// Game.Redealt, dealing anew every card its player can't see, so that it
+// learns nothing it shouldn't.
+type Searcher interface {
+	Sit(g *game.Game)
+}
+
+// A Table seats bots at a game, acting for them whenever it is their move.
+// Seats without a bot are left to Fallback, if any.
+type Table struct {
+	seats map[string]Bot
+	game  *game.Game
+	// What has been refused of each bot during the move being made.
+	refusals map[string]int
+	random   *Random
+
+	Fallback game.ChoiceGetter
+}
+
+// NewTable seats bots by player name. A bot whose moves keep being refused
+// has random ones made for it with rng.
+// Its Choose method must be given to the GameBuilder with SetChoiceGetter
+// before the game is made.
+func NewTable(rng *rand.Rand, seats map[string]Bot) *Table {
+	return &Table{
+		seats:    seats,
+		refusals: make(map[string]int),
+		random:   NewRandom(rng),
+	}
+}
+
+// Choose answers a prompt made to a seated bot.
+func (t *Table) Choose(p game.Prompt) []string {
+	b, ok := t.seats[p.Player]
+	if !ok {
+		if t.Fallback == nil {
+			panic(fmt.Sprintf("No bot is seated as %s", p.Player))
+		}
+		return t.Fallback(p)
+	}
+	if t.refusals[p.Player] >= maxRefusals {
+		return t.random.Answer(t.game.View(p.Player), p)
+	}
+	return b.Answer(t.game.View(p.Player), p)
+}
+
+// refuse tells the bot seated as name, if any, why the game refused what it chose.
+func (t *Table) refuse(name string, err error) {
+	b, ok := t.seats[name]
+	if !ok {
+		return
+	}
+	t.refusals[name]++
+	if r, ok := b.(Refusable); ok {
+		r.Refused(err)
+	}
+}
+
+// Formatter wraps f so that seated Observers are told what their players see.
+// The game must be made with it for Observers to learn anything.
+func (t *Table) Formatter(f format.Formatter) format.Formatter {
+	return observingFormatter{Formatter: f, table: t}
+}
+
+func (t *Table) observer(name string) (Observer, bool) {
+	o, ok := t.seats[name].(Observer)
+	return o, ok
+}
+
+func (t *Table) observers() []Observer {
+	observers := make([]Observer, 0, len(t.seats))
+	for name := range t.seats {
+		if o, ok := t.observer(name); ok {
+			observers = append(observers, o)
+		}
+	}
+	return observers
+}
+
+// Sit tells the table which game its bots are playing.
+func (t *Table) Sit(g *game.Game) {
+	t.game = g
+	for _, b := range t.seats {
+		if s, ok := b.(Searcher); ok {
+			s.Sit(g)
+		}
+	}
+}
+
+// Step makes one move for the player who must act now, if that player is a bot.
+// It returns false if the game is over or a player without a bot must act.
+// A bot whose moves keep being refused has a random one made for it.
+func (t *Table) Step() (bool, error) {
+	if len(t.game.Winners()) > 0 {
+		return false, nil
+	}
+
+	active := t.game.ActivePlayerName()
+	b, ok := t.seats[active]
+	if !ok {
+		return false, nil
+	}
+
+	t.refusals = make(map[string]int)
+	for t.refusals[active] < maxRefusals {
+		err := t.game.PerformAs(active, b.Act(t.game.View(active)))
+		if err == nil {
+			return true, nil
+		}
+		t.refuse(active, err)
+	}
+
+	action := t.random.Act(t.game.View(active))
+	if err := t.game.PerformAs(active, action); err != nil {
+		return false, fmt.Errorf("%s's random move %s: %s", active, action, err)
+	}
+	return true, nil
+}
+
+// Play sits at a game and plays it to the end.
+// Every player must have a bot.
+func (t *Table) Play(g *game.Game) error {
+	t.Sit(g)
+	for len(g.Winners()) == 0 {
+		moved, err := t.Step()
+		if err != nil {
+			return err
+		}
+		if !moved {
+			return fmt.Errorf("No bot is seated as %s", g.ActivePlayerName())
+		}
+	}
+	return nil
+}
+
+type observingFormatter struct {
+	format.Formatter
+	table *Table
+}
+
+func (of observingFormatter) Error(player string, e error) error {
+	of.table.refuse(player, e)
+	return of.Formatter.Error(player, e)
+}
+
+func (of observingFormatter) SwapOrNot(swapper, swapee string) error {
+	for _, o := range of.table.observers() {
+		o.Swapped(swapper, game.TargetName(swapee))
+	}
+	return of.Formatter.SwapOrNot(swapper, swapee)
+}
+
+func (of observingFormatter) SwapOrNotOthers(swapper, first, second string) error {
+	for _, o := range of.table.observers() {
+		o.Swapped(game.TargetName(first), game.TargetName(second))
+	}
+	return of.Formatter.SwapOrNotOthers(swapper, first, second)
+}
+
+func (of observingFormatter) TellOwnCard(peeker string, r role.Role) error {
+	if o, ok := of.table.observer(peeker); ok {
+		o.Shown(peeker, r)
+	}
+	return of.Formatter.TellOwnCard(peeker, r)
+}
+
+func (of observingFormatter) TellCard(peeker, whoseCard string, r role.Role) error {
+	if o, ok := of.table.observer(peeker); ok {
+		o.Shown(game.TargetName(whoseCard), r)
+	}
+	return of.Formatter.TellCard(peeker, whoseCard, r)
+}
+
+func (of observingFormatter) ShowStartingCard(whose string, r role.Role) error {
+	for _, o := range of.table.observers() {
+		o.Shown(game.TargetName(whose), r)
+	}
+	return of.Formatter.ShowStartingCard(whose, r)
+}
+
+func (of observingFormatter) GoodClaim(claimant string, r role.Role) error {
+	for _, o := range of.table.observers() {
+		o.Shown(claimant, r)
+	}
+	return of.Formatter.GoodClaim(claimant, r)
+}
+
+func (of observingFormatter) BadClaim(claimant string, had, want role.Role) error {
+	for _, o := range of.table.observers() {
+		o.Shown(claimant, had)
+	}
+	return of.Formatter.BadClaim(claimant, had, want)
+}
@@ -0,0 +1,310 @@
+package bot
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"github.com/petertseng/mascarade/belief"
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+const (
+	// How sure a Knowing bot must be of its own card to claim it.
+	claimCertainty = 0.7
+	// How sure a Knowing bot must be that it has the claimed role to challenge.
+	challengeCertainty = 0.6
+	// A Knowing bot that is less sure than this of its own card would rather peek.
+	peekCertainty = 0.5
+	// A card a Knowing bot is less sure of than this might as well be unknown.
+	guessCertainty = 0.2
+)
+
+// Knowing keeps track of what its player has seen of the cards and how they
+// may have moved since, and plays according to what it believes.
+// It must be seated at a Table whose Formatter the game was made with.
+// If what it has seen makes no sense, it plays at random.
+type Knowing struct {
+	rng     *rand.Rand
+	self    string
+	tracker *belief.Tracker
+	// What the bot believes, as of the view it was last given.
+	known  *belief.Beliefs
+	random *Random
+
+	// Set while the bot's own decision to swap or not is waiting to be announced.
+	ownSwap *bool
+	// The card the bot looked at with the Spy's power.
+	spied string
+}
+
+func NewKnowing(rng *rand.Rand) *Knowing {
+	return &Knowing{rng: rng, tracker: belief.NewTracker(), random: NewRandom(rng)}
+}
+
+func (b *Knowing) Swapped(a, c string) {
+	chance := 0.5
+	if b.ownSwap != nil {
+		chance = 0
+		if *b.ownSwap {
+			chance = 1
+		}
+		b.ownSwap = nil
+	}
+	b.tracker.Swapped(a, c, chance)
+}
+
+func (b *Knowing) Shown(whose string, r role.Role) {
+	b.tracker.Shown(whose, r)
+}
+
+// see brings what the bot believes up to date with everything it has been
+// told, as the player whose view v is.
+func (b *Knowing) see(v game.View) error {
+	b.self = v.Self
+	known, err := b.tracker.Beliefs(v)
+	if err != nil {
+		return err
+	}
+	b.known = known
+	return nil
+}
+
+func (b *Knowing) Act(v game.View) game.Action {
+	advice, err := b.Advise(v)
+	if err != nil {
+		return b.random.Act(v)
+	}
+	return b.swapping(advice.Action)
+}
+
+// Advise chooses a move for the player whose view it is, and says why.
+// Unlike Act, it doesn't remember a swap it chooses, so it can advise a player it doesn't act for.
+func (b *Knowing) Advise(v game.View) (Advice, error) {
+	if err := b.see(v); err != nil {
+		return Advice{}, err
+	}
+
+	if v.Claimant != "" {
+		chance := b.known.Chance(v.Self, v.ClaimedRole)
+		if chance >= challengeCertainty {
+			return Advice{
+				Action: game.Action{Kind: game.ChallengeAction},
+				Reason: fmt.Sprintf("You are %s sure you are the %s.", percent(chance), v.ClaimedRole),
+			}, nil
+		}
+		return Advice{
+			Action: game.Action{Kind: game.NoChallengeAction},
+			Reason: fmt.Sprintf("You are only %s sure you are the %s, and being wrong costs a coin.", percent(chance), v.ClaimedRole),
+		}, nil
+	}
+
+	own, certainty := b.known.Likeliest(v.Self)
+	if !v.MustSwap() {
+		if certainty >= claimCertainty && own.CanAnnounce() && worth(v, own) > 0 {
+			return Advice{
+				Action: game.Action{Kind: game.ClaimAction, Role: own},
+				Reason: fmt.Sprintf("You are %s sure you are the %s, worth about %g coins now.", percent(certainty), own, worth(v, own)),
+			}, nil
+		}
+		if certainty < peekCertainty {
+			reason := "You have no idea what your card is."
+			if certainty >= guessCertainty {
+				reason = fmt.Sprintf("You are only %s sure you are the %s.", percent(certainty), own)
+			}
+			return Advice{Action: game.Action{Kind: game.PeekAction}, Reason: reason}, nil
+		}
+	}
+
+	if target, ok := b.betterCard(v); ok {
+		theirs, _ := b.known.Likeliest(target)
+		return Advice{
+			Action: game.Action{Kind: game.SwapAction, Target: target, ActuallySwap: true},
+			Reason: fmt.Sprintf("%s is likely the %s, which is better than your card.", target, theirs),
+		}, nil
+	}
+
+	action := b.chooseSwap(v)
+	reason := "You see no better card, but you don't know yours well enough to keep it; really swapping keeps the others guessing."
+	if !action.ActuallySwap {
+		reason = "You see no better card; pretending to swap keeps the others guessing."
+	}
+	if v.MustSwap() {
+		reason = "You may only swap (or not) this turn. " + reason
+	}
+	return Advice{Action: action, Reason: reason}, nil
+}
+
+// swap chooses a swap and remembers whether it will really happen.
+func (b *Knowing) swap(v game.View) game.Action {
+	return b.swapping(b.chooseSwap(v))
+}
+
+// swapping remembers whether the bot's own swap, if action is one, will really happen.
+func (b *Knowing) swapping(action game.Action) game.Action {
+	if action.Kind == game.SwapAction {
+		b.ownSwap = &action.ActuallySwap
+	}
+	return action
+}
+
+// Refused forgets the bot's own swap, which the game did not allow, so that
+// the next swap announced isn't taken for it.
+func (b *Knowing) Refused(err error) {
+	b.ownSwap = nil
+}
+
+// chooseSwap takes a card that is known to be better than the bot's own, or
+// else keeps a good card while pretending to swap it away.
+func (b *Knowing) chooseSwap(v game.View) game.Action {
+	if target, ok := b.betterCard(v); ok {
+		return game.Action{Kind: game.SwapAction, Target: target, ActuallySwap: true}
+	}
+
+	targets := v.SwapTargets()
+	_, certainty := b.known.Likeliest(v.Self)
+	return game.Action{
+		Kind:         game.SwapAction,
+		Target:       targets[b.rng.Intn(len(targets))],
+		ActuallySwap: certainty < claimCertainty && b.rng.Intn(2) == 0,
+	}
+}
+
+// betterCard finds the card known well enough to be worth most, if it is worth more than the bot's own.
+func (b *Knowing) betterCard(v game.View) (string, bool) {
+	value := func(r role.Role) float64 { return worth(v, r) }
+	best, bestWorth := "", b.known.Expected(v.Self, value)
+	for _, target := range v.SwapTargets() {
+		if _, certainty := b.known.Likeliest(target); certainty < claimCertainty {
+			continue
+		}
+		if w := b.known.Expected(target, value); w > bestWorth {
+			best, bestWorth = target, w
+		}
+	}
+	return best, best != ""
+}
+
+func (b *Knowing) Answer(v game.View, p game.Prompt) []string {
+	if err := b.see(v); err != nil {
+		return b.random.Answer(v, p)
+	}
+
+	switch p.Kind {
+	case game.ChooseCoinOwner:
+		richest := richestOf(v, p.Choices)
+		if p.Power == role.Witch {
+			self, _ := v.Player(v.Self)
+			if rich, _ := v.Player(richest); rich.Coins <= self.Coins {
+				return []string{v.Self}
+			}
+		}
+		return []string{richest}
+
+	case game.ChoosePlayers:
+		return []string{richestOf(v, p.Choices)}
+
+	case game.ChooseSwappables:
+		if p.Num == 1 {
+			b.spied = b.leastKnown(p.Choices)
+			return []string{b.spied}
+		}
+		choices := make([]string, p.Num)
+		for i, j := range b.rng.Perm(len(p.Choices))[:p.Num] {
+			choices[i] = p.Choices[j]
+		}
+		return choices
+
+	case game.ChooseRole:
+		if own, certainty := b.known.Likeliest(v.Self); certainty > 0 {
+			return []string{own.String()}
+		}
+		return []string{v.Roles[b.rng.Intn(len(v.Roles))].String()}
+
+	case game.ChooseBoolean:
+		actuallySwap := b.rng.Intn(2) == 0
+		if p.Power == role.Spy {
+			value := func(r role.Role) float64 { return worth(v, r) }
+			actuallySwap = b.known.Expected(b.spied, value) > b.known.Expected(v.Self, value)
+		}
+		b.ownSwap = &actuallySwap
+		return []string{strconv.FormatBool(actuallySwap)}
+	}
+
+	return nil
+}
+
+// leastKnown picks the card the bot knows least about.
+func (b *Knowing) leastKnown(choices []string) string {
+	least, leastCertainty := "", 2.0
+	for _, i := range b.rng.Perm(len(choices)) {
+		if _, certainty := b.known.Likeliest(choices[i]); certainty < leastCertainty {
+			least, leastCertainty = choices[i], certainty
+		}
+	}
+	return least
+}
+
+// richestOf picks the richest of the named players.
+func richestOf(v game.View, names []string) string {
+	richest, richestCoins := names[0], uint64(0)
+	for _, name := range names {
+		if p, ok := v.Player(name); ok && p.Coins > richestCoins {
+			richest, richestCoins = name, p.Coins
+		}
+	}
+	return richest
+}
+
+// worth estimates how many coins using a role's power would be worth to the
+// player whose view it is, right now.
+func worth(v game.View, r role.Role) float64 {
+	self, _ := v.Player(v.Self)
+	richestOther := uint64(0)
+	for _, p := range v.Players {
+		if p.Name != v.Self && p.Coins > richestOther {
+			richestOther = p.Coins
+		}
+	}
+
+	switch r {
+	case role.Judge:
+		return float64(v.Courthouse)
+	case role.Bishop:
+		if richestOther < 2 {
+			return float64(richestOther)
+		}
+		return 2
+	case role.King:
+		return 3
+	case role.Fool:
+		return 1
+	case role.Queen:
+		return 2
+	case role.Thief:
+		return 2
+	case role.Witch:
+		if richestOther > self.Coins {
+			return float64(richestOther - self.Coins)
+		}
+		return 0
+	case role.Spy:
+		return 0
+	case role.Peasant:
+		return 1
+	case role.Cheat:
+		if self.Coins >= v.Rules.CheatCoins {
+			return float64(v.Rules.WinningCoins)
+		}
+		return 0
+	case role.Inquisitor:
+		return 2
+	case role.Widow:
+		if self.Coins < v.Rules.WidowCoins {
+			return float64(v.Rules.WidowCoins - self.Coins)
+		}
+		return 0
+	}
+	return 0
+}
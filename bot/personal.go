@@ -0,0 +1,114 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+
+	"github.com/petertseng/mascarade/game"
+)
+
+// A Personality tunes how a Personal bot plays. Every trait is between 0 and 1.
+type Personality struct {
+	// Chance of claiming the role worth most when the bot is not sure of its own card.
+	Bluff float64 `json:"bluff"`
+	// How readily the bot challenges: at 0 it must be as sure as a Knowing
+	// bot that it has the claimed role, and at 1 it challenges every claim.
+	Aggression float64 `json:"aggression"`
+	// Chance of really swapping when no card in sight is known to be better than the bot's own.
+	Swap float64 `json:"swap"`
+	// Chance of peeking when the bot is not sure of its own card.
+	Peek float64 `json:"peek"`
+}
+
+func (p Personality) validate() error {
+	traits := []struct {
+		name  string
+		value float64
+	}{
+		{"bluff", p.Bluff},
+		{"aggression", p.Aggression},
+		{"swap", p.Swap},
+		{"peek", p.Peek},
+	}
+	for _, t := range traits {
+		if t.value < 0 || t.value > 1 {
+			return fmt.Errorf("%s is %g, but must be between 0 and 1", t.name, t.value)
+		}
+	}
+	return nil
+}
+
+// LoadPersonalities reads a profile file: a JSON object mapping names to personalities, such as
+//
+//	{"cautious": {"bluff": 0.05, "aggression": 0, "swap": 0.2, "peek": 0.9}}
+func LoadPersonalities(path string) (map[string]Personality, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var personalities map[string]Personality
+	if err := json.NewDecoder(f).Decode(&personalities); err != nil {
+		return nil, fmt.Errorf("Bad profile in %s: %s", path, err)
+	}
+
+	names := make([]string, 0, len(personalities))
+	for name := range personalities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := personalities[name].validate(); err != nil {
+			return nil, fmt.Errorf("Bad personality %s in %s: %s", name, path, err)
+		}
+	}
+	return personalities, nil
+}
+
+// Personal plays as Knowing does when it knows what to do, and by its
+// Personality when it doesn't.
+type Personal struct {
+	*Knowing
+	personality Personality
+}
+
+func NewPersonal(rng *rand.Rand, personality Personality) *Personal {
+	return &Personal{Knowing: NewKnowing(rng), personality: personality}
+}
+
+func (b *Personal) Act(v game.View) game.Action {
+	if err := b.see(v); err != nil {
+		return b.random.Act(v)
+	}
+
+	if v.Claimant != "" {
+		needed := challengeCertainty * (1 - b.personality.Aggression)
+		if b.known.Chance(v.Self, v.ClaimedRole) >= needed {
+			return game.Action{Kind: game.ChallengeAction}
+		}
+		return game.Action{Kind: game.NoChallengeAction}
+	}
+
+	own, certainty := b.known.Likeliest(v.Self)
+	if !v.MustSwap() {
+		if certainty >= claimCertainty && own.CanAnnounce() && worth(v, own) > 0 {
+			return game.Action{Kind: game.ClaimAction, Role: own}
+		}
+		if certainty < claimCertainty && b.rng.Float64() < b.personality.Bluff {
+			return game.Action{Kind: game.ClaimAction, Role: bluffFor(v)}
+		}
+		if certainty < peekCertainty && b.rng.Float64() < b.personality.Peek {
+			return game.Action{Kind: game.PeekAction}
+		}
+	}
+
+	action := b.chooseSwap(v)
+	if _, ok := b.betterCard(v); !ok {
+		action.ActuallySwap = b.rng.Float64() < b.personality.Swap
+	}
+	return b.swapping(action)
+}
@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"math/rand"
+	"strconv"
+
+	"github.com/petertseng/mascarade/game"
+)
+
+// Random plays uniformly at random among the moves the game would accept.
+type Random struct {
+	rng *rand.Rand
+}
+
+func NewRandom(rng *rand.Rand) *Random {
+	return &Random{rng: rng}
+}
+
+func (b *Random) Act(v game.View) game.Action {
+	actions := v.LegalActions()
+	return actions[b.rng.Intn(len(actions))]
+}
+
+func (b *Random) Answer(v game.View, p game.Prompt) []string {
+	switch p.Kind {
+	case game.ChooseRole:
+		return []string{v.Roles[b.rng.Intn(len(v.Roles))].String()}
+	case game.ChooseBoolean:
+		return []string{strconv.FormatBool(b.rng.Intn(2) == 0)}
+	}
+
+	choices := make([]string, p.Num)
+	for i, j := range b.rng.Perm(len(p.Choices))[:p.Num] {
+		choices[i] = p.Choices[j]
+	}
+	return choices
+}
@@ -0,0 +1,360 @@
+package bot
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/petertseng/mascarade/format"
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+const (
+	// How much a Searching bot favours moves it has tried less often.
+	exploration = 0.7
+	// Playouts that go on for this many turns are given up as having no winner.
+	playoutTurns = 200
+)
+
+// A playout is how everyone moves while a copy of the game is played out.
+// Everyone acts only on what they could know in the copy: a player claims
+// or challenges with a card they are sure is theirs, and otherwise moves at
+// random. A player is sure of their card once they see it, until it may
+// have been swapped without them knowing.
+type playout struct {
+	*Random
+	game *game.Game
+	// The card each player is sure they have.
+	sure map[string]role.Role
+	// The player who is swapping but chose not to really swap, if any.
+	kept string
+}
+
+// newPlayout copies g so that it can be played out, with cards dealt anew as
+// by Game.Redealt. Players start out sure of the cards given in sure.
+func newPlayout(g *game.Game, cards, sure map[string]role.Role, rng *rand.Rand) (*playout, error) {
+	p := &playout{Random: NewRandom(rng), sure: make(map[string]role.Role, len(sure))}
+	for name, r := range sure {
+		p.sure[name] = r
+	}
+	c, err := g.Redealt(playoutFormatter{Formatter: format.NewNop(), playout: p}, func(prompt game.Prompt) []string {
+		return p.Answer(p.game.View(prompt.Player), prompt)
+	}, cards)
+	if err != nil {
+		return nil, err
+	}
+	p.game = c
+	return p, nil
+}
+
+// perform makes a move in the copy.
+func (p *playout) perform(a game.Action) error {
+	if a.Kind == game.SwapAction && !a.ActuallySwap {
+		p.kept = p.game.ActivePlayerName()
+	}
+	err := p.game.Perform(a)
+	p.kept = ""
+	return err
+}
+
+// finish plays the copy out to the end, or until lastTurn, and tells what share of the win player got.
+func (p *playout) finish(player string, lastTurn uint) float64 {
+	for len(p.game.Winners()) == 0 && p.game.View(player).TurnCount < lastTurn {
+		if err := p.perform(p.Act(p.game.View(p.game.ActivePlayerName()))); err != nil {
+			panic(err)
+		}
+	}
+
+	for _, winner := range p.game.Winners() {
+		if winner == player {
+			return 1 / float64(len(p.game.Winners()))
+		}
+	}
+	return 0
+}
+
+func (p *playout) Act(v game.View) game.Action {
+	own, sure := p.sure[v.Self]
+
+	if v.Claimant != "" {
+		if sure && own == v.ClaimedRole {
+			return game.Action{Kind: game.ChallengeAction}
+		}
+		return game.Action{Kind: game.NoChallengeAction}
+	}
+
+	if v.MustSwap() {
+		return p.Random.Act(v)
+	}
+	if sure && own.CanAnnounce() && worth(v, own) > 0 && p.rng.Intn(4) != 0 {
+		return game.Action{Kind: game.ClaimAction, Role: own}
+	}
+	if !sure && p.rng.Intn(2) == 0 {
+		return game.Action{Kind: game.PeekAction}
+	}
+	return p.Random.Act(v)
+}
+
+// A playoutFormatter keeps track of which players are sure of their cards.
+type playoutFormatter struct {
+	format.Formatter
+	playout *playout
+}
+
+func (pf playoutFormatter) SwapOrNot(swapper, swapee string) error {
+	if swapper != pf.playout.kept {
+		delete(pf.playout.sure, swapper)
+	}
+	delete(pf.playout.sure, swapee)
+	return nil
+}
+
+func (pf playoutFormatter) SwapOrNotOthers(swapper, first, second string) error {
+	delete(pf.playout.sure, first)
+	delete(pf.playout.sure, second)
+	return nil
+}
+
+func (pf playoutFormatter) TellOwnCard(peeker string, r role.Role) error {
+	pf.playout.sure[peeker] = r
+	return nil
+}
+
+func (pf playoutFormatter) ShowStartingCard(whose string, r role.Role) error {
+	pf.playout.sure[whose] = r
+	return nil
+}
+
+func (pf playoutFormatter) GoodClaim(claimant string, r role.Role) error {
+	pf.playout.sure[claimant] = r
+	return nil
+}
+
+func (pf playoutFormatter) BadClaim(claimant string, had, want role.Role) error {
+	pf.playout.sure[claimant] = had
+	return nil
+}
+
+// Searching chooses its moves by information-set Monte Carlo tree search.
+// Over and over, it deals out the cards it can't see in a way that agrees
+// with what it knows, then plays the game out on a copy, as a playout.
+// It remembers what it has seen and answers prompts just as Knowing does.
+type Searching struct {
+	*Knowing
+	game       *game.Game
+	iterations int
+}
+
+// NewSearching makes a bot that plays out iterations copies of the game for every move.
+func NewSearching(rng *rand.Rand, iterations int) *Searching {
+	return &Searching{Knowing: NewKnowing(rng), iterations: iterations}
+}
+
+func (b *Searching) Sit(g *game.Game) {
+	b.game = g
+}
+
+// A searchNode is a position reached by some sequence of moves.
+// Its reward is the total won by the player who made the move leading to it.
+type searchNode struct {
+	visits   int
+	reward   float64
+	children map[game.Action]*searchNode
+}
+
+func newSearchNode() *searchNode {
+	return &searchNode{children: make(map[game.Action]*searchNode)}
+}
+
+func (b *Searching) Act(v game.View) game.Action {
+	actions := v.LegalActions()
+	if len(actions) == 1 || b.game == nil {
+		return b.Knowing.Act(v)
+	}
+	if err := b.see(v); err != nil {
+		return b.random.Act(v)
+	}
+
+	root := newSearchNode()
+	for i := 0; i < b.iterations; i++ {
+		b.iterate(root, v)
+	}
+
+	best, bestVisits := actions[0], -1
+	for _, a := range actions {
+		if child, ok := root.children[a]; ok && child.visits > bestVisits {
+			best, bestVisits = a, child.visits
+		}
+	}
+
+	if best.Kind == game.SwapAction {
+		b.ownSwap = &best.ActuallySwap
+	}
+	return best
+}
+
+// iterate plays out one copy of the game, growing the tree by one position.
+// The tree holds only what the bot can see, so the other players' swaps are
+// told apart by their targets alone; whether they really swap is left to
+// chance in the copy.
+func (b *Searching) iterate(root *searchNode, v game.View) {
+	cards := b.sample(v)
+	rollout, err := newPlayout(b.game, cards, b.sure(v, cards), b.rng)
+	if err != nil {
+		panic(err)
+	}
+	c := rollout.game
+
+	lastTurn := v.TurnCount + playoutTurns
+	path := []*searchNode{root}
+	movers := make([]string, 0)
+
+	node := root
+	for len(c.Winners()) == 0 {
+		mover := c.ActivePlayerName()
+		moves := c.View(mover).LegalActions()
+		if mover != v.Self {
+			moves = seenMoves(moves)
+		}
+
+		untried := make([]game.Action, 0)
+		for _, a := range moves {
+			if _, ok := node.children[a]; !ok {
+				untried = append(untried, a)
+			}
+		}
+
+		var move game.Action
+		expand := len(untried) > 0
+		if expand {
+			move = untried[b.rng.Intn(len(untried))]
+			node.children[move] = newSearchNode()
+		} else {
+			move = selectUCB(node, moves)
+		}
+
+		action := move
+		if mover != v.Self && action.Kind == game.SwapAction {
+			action.ActuallySwap = b.rng.Intn(2) == 0
+		}
+		if err := rollout.perform(action); err != nil {
+			panic(err)
+		}
+		node = node.children[move]
+		path = append(path, node)
+		movers = append(movers, mover)
+
+		if expand {
+			break
+		}
+	}
+
+	rollout.finish(v.Self, lastTurn)
+
+	rewards := make(map[string]float64)
+	for _, winner := range c.Winners() {
+		rewards[winner] = 1 / float64(len(c.Winners()))
+	}
+
+	root.visits++
+	for i, n := range path[1:] {
+		n.visits++
+		n.reward += rewards[movers[i]]
+	}
+}
+
+// seenMoves is what can be seen of another player's moves: each swap only by its target.
+func seenMoves(actions []game.Action) []game.Action {
+	seen := make([]game.Action, 0, len(actions))
+	for _, a := range actions {
+		if a.Kind == game.SwapAction && a.ActuallySwap {
+			continue
+		}
+		seen = append(seen, a)
+	}
+	return seen
+}
+
+// sure tells which of the dealt cards the players are taken to be sure of
+// as the copy starts: those the bot is sure of too.
+func (b *Searching) sure(v game.View, cards map[string]role.Role) map[string]role.Role {
+	sure := make(map[string]role.Role, len(v.Players))
+	for _, p := range v.Players {
+		if r := cards[p.Name]; b.known.Chance(p.Name, r) >= claimCertainty {
+			sure[p.Name] = r
+		}
+	}
+	return sure
+}
+
+// selectUCB picks the move that best balances how well it has done against how little it has been tried.
+func selectUCB(node *searchNode, actions []game.Action) game.Action {
+	best, bestScore := actions[0], math.Inf(-1)
+	for _, a := range actions {
+		child := node.children[a]
+		score := child.reward/float64(child.visits) + exploration*math.Sqrt(math.Log(float64(node.visits))/float64(child.visits))
+		if score > bestScore {
+			best, bestScore = a, score
+		}
+	}
+	return best
+}
+
+// sample deals out every card in a way that agrees with what the bot knows,
+// starting with the cards it is surest of.
+func (b *Searching) sample(v game.View) map[string]role.Role {
+	deck := make(map[role.Role]int, len(v.Deck))
+	for r, copies := range v.Deck {
+		deck[r] = copies
+	}
+
+	cards := make([]string, 0, len(v.Players)+len(v.TableCards))
+	for _, p := range v.Players {
+		cards = append(cards, p.Name)
+	}
+	cards = append(cards, v.TableCards...)
+	b.rng.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+	sort.SliceStable(cards, func(i, j int) bool {
+		_, certainty := b.known.Likeliest(cards[i])
+		_, other := b.known.Likeliest(cards[j])
+		return certainty > other
+	})
+
+	dealt := make(map[string]role.Role, len(cards))
+	for _, card := range cards {
+		weights := make(map[role.Role]float64)
+		total := 0.0
+		for _, r := range v.Roles {
+			if deck[r] == 0 {
+				continue
+			}
+			w := b.known.Chance(card, r)
+			if w <= 0 {
+				// Nothing known says it could be this, but something must be left to deal.
+				w = 1e-9
+			}
+			weights[r] = w
+			total += w
+		}
+
+		pick := b.rng.Float64() * total
+		var dealtRole role.Role
+		for _, r := range v.Roles {
+			w, ok := weights[r]
+			if !ok {
+				continue
+			}
+			dealtRole = r
+			if pick < w {
+				break
+			}
+			pick -= w
+		}
+
+		dealt[card] = dealtRole
+		deck[dealtRole]--
+	}
+
+	return dealt
+}
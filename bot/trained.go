@@ -0,0 +1,199 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+// The moves a policy chooses between when deciding whether to challenge.
+const (
+	challengeMove = iota
+	passMove
+	numChallengeMoves
+)
+
+// The moves a policy chooses between on a turn when it need not swap.
+// Claiming claims the role the bot believes it has, or bluffs if it has no idea.
+const (
+	claimMove = iota
+	peekMove
+	swapMove
+	numTurnMoves
+)
+
+// A Policy says how likely a bot is to make each move, for every situation
+// it might be in, as learned by a Trainer. Situations are summed up by what
+// the bot believes of the role in question, how sure it is, how many coins
+// it has, and how far along the game is.
+type Policy struct {
+	// Chances of challengeMove and passMove.
+	Challenge map[string][]float64 `json:"challenge"`
+	// Chances of claimMove, peekMove and swapMove.
+	Turn map[string][]float64 `json:"turn"`
+}
+
+func LoadPolicy(path string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var p Policy
+	if err := json.NewDecoder(f).Decode(&p); err != nil {
+		return nil, fmt.Errorf("Bad policy in %s: %s", path, err)
+	}
+	return &p, nil
+}
+
+func (p *Policy) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(p); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func certaintyBucket(certainty float64) string {
+	switch {
+	case certainty >= claimCertainty:
+		return "sure"
+	case certainty >= 0.4:
+		return "likely"
+	case certainty >= guessCertainty:
+		return "unsure"
+	}
+	return "unknown"
+}
+
+func coinsBucket(coins uint64) int {
+	switch {
+	case coins <= 2:
+		return 0
+	case coins <= 5:
+		return 1
+	case coins <= 9:
+		return 2
+	}
+	return 3
+}
+
+func turnBucket(turn uint) int {
+	switch {
+	case turn < 8:
+		return 0
+	case turn < 16:
+		return 1
+	}
+	return 2
+}
+
+// challengeSituation sums up a decision whether to challenge.
+func (b *Knowing) challengeSituation(v game.View) string {
+	self, _ := v.Player(v.Self)
+	return fmt.Sprintf("%s %s %d %d", v.ClaimedRole, certaintyBucket(b.known.Chance(v.Self, v.ClaimedRole)), coinsBucket(self.Coins), turnBucket(v.TurnCount))
+}
+
+// turnSituation sums up a turn on which the bot need not swap.
+func (b *Knowing) turnSituation(v game.View) string {
+	self, _ := v.Player(v.Self)
+	own, certainty := b.known.Likeliest(v.Self)
+	believed := "?"
+	if certainty >= guessCertainty {
+		believed = own.String()
+	}
+	return fmt.Sprintf("%s %s %d %d", believed, certaintyBucket(certainty), coinsBucket(self.Coins), turnBucket(v.TurnCount))
+}
+
+// claimFor gives the role to claim: the one the bot believes it has, or
+// failing that, the one that would be worth most.
+func (b *Knowing) claimFor(v game.View) role.Role {
+	own, certainty := b.known.Likeliest(v.Self)
+	if certainty >= guessCertainty && own.CanAnnounce() {
+		return own
+	}
+	return bluffFor(v)
+}
+
+// bluffFor gives the role that would be worth most to claim, whatever the bot's card.
+func bluffFor(v game.View) role.Role {
+	best, bestWorth := role.NoSuchRole, -1.0
+	for _, r := range v.Roles {
+		if w := worth(v, r); r.CanAnnounce() && w > bestWorth {
+			best, bestWorth = r, w
+		}
+	}
+	return best
+}
+
+// challengeAction turns a move into an action, when deciding whether to challenge.
+func challengeAction(move int) game.Action {
+	if move == challengeMove {
+		return game.Action{Kind: game.ChallengeAction}
+	}
+	return game.Action{Kind: game.NoChallengeAction}
+}
+
+// turnAction turns a move into an action, on a turn the bot need not swap.
+// A swap is not remembered, so it must be passed to b.swapping if it is made.
+func (b *Knowing) turnAction(v game.View, move int) game.Action {
+	switch move {
+	case claimMove:
+		return game.Action{Kind: game.ClaimAction, Role: b.claimFor(v)}
+	case peekMove:
+		return game.Action{Kind: game.PeekAction}
+	}
+	return b.chooseSwap(v)
+}
+
+func pick(rng *rand.Rand, chances []float64) int {
+	x := rng.Float64()
+	for move, chance := range chances {
+		if x < chance {
+			return move
+		}
+		x -= chance
+	}
+	return len(chances) - 1
+}
+
+// Trained decides whether to challenge, and whether to claim, peek or swap,
+// by a Policy. Whenever the policy has nothing to say, it plays as Knowing does.
+type Trained struct {
+	*Knowing
+	policy *Policy
+}
+
+func NewTrained(rng *rand.Rand, policy *Policy) *Trained {
+	return &Trained{Knowing: NewKnowing(rng), policy: policy}
+}
+
+func (b *Trained) Act(v game.View) game.Action {
+	if err := b.see(v); err != nil {
+		return b.random.Act(v)
+	}
+
+	if v.Claimant != "" {
+		if chances, ok := b.policy.Challenge[b.challengeSituation(v)]; ok {
+			return challengeAction(pick(b.rng, chances))
+		}
+		return b.Knowing.Act(v)
+	}
+
+	if v.MustSwap() {
+		return b.swap(v)
+	}
+	if chances, ok := b.policy.Turn[b.turnSituation(v)]; ok {
+		return b.swapping(b.turnAction(v, pick(b.rng, chances)))
+	}
+	return b.Knowing.Act(v)
+}
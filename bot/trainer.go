@@ -0,0 +1,201 @@
+package bot
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/petertseng/mascarade/format"
+	"github.com/petertseng/mascarade/game"
+)
+
+// How many turns past a decision a Trainer plays out before giving up on a winner.
+const trainingTurns = 100
+
+// regrets holds, for every situation, how much better off a player would
+// have been for always making each move, and how often it made each move.
+type regrets struct {
+	regret      map[string][]float64
+	strategySum map[string][]float64
+	moves       int
+}
+
+func newRegrets(moves int) *regrets {
+	return &regrets{
+		regret:      make(map[string][]float64),
+		strategySum: make(map[string][]float64),
+		moves:       moves,
+	}
+}
+
+// strategy matches chances to how much each move is regretted.
+func (r *regrets) strategy(situation string) []float64 {
+	if _, ok := r.regret[situation]; !ok {
+		r.regret[situation] = make([]float64, r.moves)
+		r.strategySum[situation] = make([]float64, r.moves)
+	}
+
+	chances := make([]float64, r.moves)
+	total := 0.0
+	for move, regret := range r.regret[situation] {
+		if regret > 0 {
+			chances[move] = regret
+			total += regret
+		}
+	}
+	for move := range chances {
+		if total > 0 {
+			chances[move] /= total
+		} else {
+			chances[move] = 1 / float64(r.moves)
+		}
+	}
+	return chances
+}
+
+func (r *regrets) update(situation string, chances, values []float64) {
+	expected := 0.0
+	for move, chance := range chances {
+		expected += chance * values[move]
+	}
+	for move, chance := range chances {
+		r.regret[situation][move] += values[move] - expected
+		r.strategySum[situation][move] += chance
+	}
+}
+
+// average gives the chances of each move averaged over all of training,
+// which is what counterfactual regret minimization settles towards.
+func (r *regrets) average() map[string][]float64 {
+	avg := make(map[string][]float64, len(r.strategySum))
+	for situation, sums := range r.strategySum {
+		total := 0.0
+		for _, sum := range sums {
+			total += sum
+		}
+		if total == 0 {
+			continue
+		}
+		chances := make([]float64, len(sums))
+		for move, sum := range sums {
+			chances[move] = sum / total
+		}
+		avg[situation] = chances
+	}
+	return avg
+}
+
+// A Trainer learns a Policy by counterfactual regret minimization over
+// games of self-play. Whenever a player makes a decision the policy covers,
+// the trainer plays out copies of the game after each move it could have
+// made, and regrets not having made those that turned out better.
+type Trainer struct {
+	rng       *rand.Rand
+	rollouts  int
+	challenge *regrets
+	turn      *regrets
+}
+
+// NewTrainer makes a trainer that plays out rollouts copies of the game for every move of every decision.
+func NewTrainer(rng *rand.Rand, rollouts int) *Trainer {
+	return &Trainer{
+		rng:       rng,
+		rollouts:  rollouts,
+		challenge: newRegrets(numChallengeMoves),
+		turn:      newRegrets(numTurnMoves),
+	}
+}
+
+// Train plays games among the given number of players with the given roles,
+// all of them learning together.
+func (t *Trainer) Train(roles []string, players int, games int) error {
+	for i := 0; i < games; i++ {
+		gameBuilder := game.NewBuilder()
+		gameBuilder.SetSeed(t.rng.Int63())
+		gameBuilder.SetHistory(false)
+		for _, name := range roles {
+			if err := gameBuilder.AddRole(name); err != nil {
+				return err
+			}
+		}
+
+		seats := make(map[string]Bot)
+		for p := 0; p < players; p++ {
+			name := fmt.Sprintf("P%d", p+1)
+			gameBuilder.AddPlayer(name)
+			seats[name] = &learner{Knowing: NewKnowing(t.rng), trainer: t}
+		}
+
+		table := NewTable(t.rng, seats)
+		gameBuilder.SetChoiceGetter(table.Choose)
+		g, err := gameBuilder.MakeGameWithFormatter(table.Formatter(format.NewNop()))
+		if err != nil {
+			return err
+		}
+		if err := table.Play(&g); err != nil {
+			return fmt.Errorf("Training game %d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// Policy gives what has been learned so far.
+func (t *Trainer) Policy() *Policy {
+	return &Policy{Challenge: t.challenge.average(), Turn: t.turn.average()}
+}
+
+// A learner plays by the trainer's current strategy, and teaches it as it goes.
+type learner struct {
+	*Knowing
+	trainer *Trainer
+	game    *game.Game
+}
+
+func (b *learner) Sit(g *game.Game) {
+	b.game = g
+}
+
+func (b *learner) Act(v game.View) game.Action {
+	if err := b.see(v); err != nil {
+		return b.random.Act(v)
+	}
+
+	if v.Claimant != "" {
+		situation := b.challengeSituation(v)
+		move := b.learn(v, b.trainer.challenge, situation, challengeAction)
+		return challengeAction(move)
+	}
+
+	if v.MustSwap() {
+		return b.swap(v)
+	}
+
+	situation := b.turnSituation(v)
+	actions := make([]game.Action, numTurnMoves)
+	for move := range actions {
+		actions[move] = b.turnAction(v, move)
+	}
+	move := b.learn(v, b.trainer.turn, situation, func(move int) game.Action { return actions[move] })
+	return b.swapping(actions[move])
+}
+
+// learn tries out every move in a situation, updates the regrets, and picks a move by the current strategy.
+func (b *learner) learn(v game.View, r *regrets, situation string, action func(move int) game.Action) int {
+	chances := r.strategy(situation)
+	values := make([]float64, len(chances))
+	for move := range values {
+		for i := 0; i < b.trainer.rollouts; i++ {
+			rollout, err := newPlayout(b.game, nil, nil, b.trainer.rng)
+			if err != nil {
+				panic(err)
+			}
+			if err := rollout.perform(action(move)); err != nil {
+				panic(err)
+			}
+			values[move] += rollout.finish(v.Self, v.TurnCount+trainingTurns)
+		}
+		values[move] /= float64(b.trainer.rollouts)
+	}
+
+	r.update(situation, chances, values)
+	return pick(b.trainer.rng, chances)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/petertseng/mascarade/bot"
+	"github.com/petertseng/mascarade/engine"
+)
+
+// mascarade-engine runs one of the built-in bots as an engine over standard
+// input and output, as a reference for anyone writing their own.
+func main() {
+	kind := flag.String("bot", "knowing", fmt.Sprintf("which bot to run, from %s", strings.Join(bot.Kinds(), ", ")))
+	flag.Parse()
+
+	b, err := bot.New(*kind, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if err := engine.Serve("mascarade "+*kind, b, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/petertseng/mascarade/sim"
+)
+
+func main() {
+	config := sim.Flags(flag.CommandLine)
+	flag.Parse()
+
+	c, err := config()
+	if err == sim.ErrNoRoles {
+		fmt.Fprintf(os.Stderr, "usage: %s -roles role1,role2,...,roleN [flags]\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	stats, err := sim.Run(c)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Seed: %d\n", c.Seed)
+	stats.Write(os.Stdout)
+}
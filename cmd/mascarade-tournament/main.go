@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/petertseng/mascarade/bot"
+	"github.com/petertseng/mascarade/tournament"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	bots := flag.String("bots", "", "comma-separated bots taking part (default every bot but searching, and those loaded)")
+	minPlayers := flag.Int("min-players", 3, "fewest players in a game")
+	maxPlayers := flag.Int("max-players", 6, "most players in a game")
+	games := flag.Int("games", 1000, "number of games to play")
+	k := flag.Float64("k", 32, "most a rating can change by in one game")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed, to repeat a tournament exactly")
+	workers := flag.Int("workers", 0, "games to play at once (default one per CPU)")
+	out := flag.String("out", "leaderboard.txt", "file to write the leaderboard to")
+	policy := flag.String("policy", "", "policy file from mascarade-train, to enter the \"trained\" bot")
+	personalities := flag.String("personalities", "", "profile file of personalities, each entering a bot by its name")
+	flag.Parse()
+
+	if err := bot.LoadExtras(*policy, *personalities); err != nil {
+		return err
+	}
+	if *bots == "" {
+		*bots = strings.Join(bot.Opponents(), ",")
+	}
+
+	standings, err := tournament.Run(tournament.Config{
+		Bots:       strings.Split(*bots, ","),
+		MinPlayers: *minPlayers,
+		MaxPlayers: *maxPlayers,
+		Games:      *games,
+		K:          *k,
+		Seed:       *seed,
+		Workers:    *workers,
+	})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "Seed: %d\n", *seed); err != nil {
+		return err
+	}
+	if err := tournament.WriteLeaderboard(f, standings); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return tournament.WriteLeaderboard(os.Stdout, standings)
+}
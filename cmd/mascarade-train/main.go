@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/petertseng/mascarade/bot"
+)
+
+// mascarade-train learns when to challenge and when to claim by self-play,
+// and saves the policy for the "trained" bot of mascarade-sim and mascarade-tournament.
+func main() {
+	players := flag.Int("players", 4, "number of players")
+	roles := flag.String("roles", "", "comma-separated roles in the game (required)")
+	games := flag.Int("games", 200, "number of games of self-play")
+	rollouts := flag.Int("rollouts", 4, "playouts of every move of every decision")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed, to repeat training exactly")
+	out := flag.String("out", "policy.json", "file to save the policy to")
+	flag.Parse()
+
+	if *roles == "" {
+		fmt.Fprintf(os.Stderr, "usage: %s -roles role1,role2,...,roleN [flags]\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	trainer := bot.NewTrainer(rand.New(rand.NewSource(*seed)), *rollouts)
+	if err := trainer.Train(strings.Split(*roles, ","), *players, *games); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	policy := trainer.Policy()
+	if err := policy.Save(*out); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Learned %d challenge and %d turn situations; saved to %s\n", len(policy.Challenge), len(policy.Turn), *out)
+}
@@ -0,0 +1,143 @@
+// Package command reads what players type: the moves they make, and their
+// answers when a power asks them to choose. It is meant for any frontend
+// where players type, whether at a terminal, in a chat, or over a network.
+//
+// A move is one of
+//
+//	swap <player_or_table> <yes|no>
+//	peek
+//	claim <role>
+//	challenge (or cc)
+//	pass
+//
+// which is how game.Action writes them, too.
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+// Usage sums up every move.
+const Usage = "<swap|peek|claim|challenge|pass> [args]"
+
+var usages = map[game.ActionKind]string{
+	game.SwapAction:        "swap <player_or_table> <yes|no>",
+	game.PeekAction:        "peek",
+	game.ClaimAction:       "claim <role>",
+	game.ChallengeAction:   "challenge",
+	game.NoChallengeAction: "pass",
+}
+
+// Usages gives how every move is written, in order.
+func Usages() []string {
+	lines := make([]string, 0, len(usages))
+	for kind := game.SwapAction; kind <= game.NoChallengeAction; kind++ {
+		lines = append(lines, usages[kind])
+	}
+	return lines
+}
+
+// kinds maps every word a move may start with to the move.
+var kinds = map[string]game.ActionKind{
+	"swap":      game.SwapAction,
+	"peek":      game.PeekAction,
+	"claim":     game.ClaimAction,
+	"challenge": game.ChallengeAction,
+	"cc":        game.ChallengeAction,
+	"pass":      game.NoChallengeAction,
+}
+
+// A UsageError is a line that isn't a move, or is missing what its move needs.
+type UsageError struct {
+	// The word the move starts with, or empty if the line starts with no move's word.
+	Command string
+	// How the move is written, or Usage if there is no such move.
+	Usage string
+}
+
+func (e *UsageError) Error() string {
+	return "usage: " + e.Usage
+}
+
+// Parse turns a line typed by the player who must act into the move it asks for.
+// Only the words are read; whether the move is allowed is up to the game.
+func Parse(line string) (game.Action, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return game.Action{}, &UsageError{Usage: Usage}
+	}
+
+	word := strings.ToLower(fields[0])
+	kind, ok := kinds[word]
+	if !ok {
+		return game.Action{}, &UsageError{Usage: Usage}
+	}
+	usage := &UsageError{Command: word, Usage: usages[kind]}
+	args := fields[1:]
+
+	switch kind {
+	case game.SwapAction:
+		// The target may be more than one word, as "Table Card 1" is.
+		if len(args) < 2 {
+			return game.Action{}, usage
+		}
+		actuallySwap, err := game.ParseBool(args[len(args)-1])
+		if err != nil {
+			return game.Action{}, err
+		}
+		return game.Action{Kind: kind, Target: strings.Join(args[:len(args)-1], " "), ActuallySwap: actuallySwap}, nil
+	case game.ClaimAction:
+		if len(args) == 0 {
+			return game.Action{}, usage
+		}
+		r, err := role.FromString(strings.Join(args, " "))
+		if err != nil {
+			return game.Action{}, err
+		}
+		return game.Action{Kind: kind, Role: r}, nil
+	}
+
+	if len(args) > 0 {
+		return game.Action{}, usage
+	}
+	return game.Action{Kind: kind}, nil
+}
+
+// ParseAnswer turns a line typed in answer to p into the words the game
+// expects for it, as a game.ChoiceGetter gives them: "true" or "false" for
+// yes or no, and roles by their full names. Whether the players or cards
+// chosen are among p's Choices is up to the game.
+func ParseAnswer(p game.Prompt, line string) ([]string, error) {
+	fields := strings.Fields(line)
+
+	switch p.Kind {
+	case game.ChooseBoolean:
+		if len(fields) != 1 {
+			return nil, fmt.Errorf("Answer yes or no")
+		}
+		b, err := game.ParseBool(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		return []string{fmt.Sprint(b)}, nil
+	case game.ChooseRole:
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("Name a role")
+		}
+		r, err := role.FromString(line)
+		if err != nil {
+			return nil, err
+		}
+		return strings.Fields(r.String()), nil
+	}
+
+	targets := game.SplitTargets(line)
+	if len(targets) != p.Num {
+		return nil, fmt.Errorf("Choose %d of %s", p.Num, strings.Join(p.Choices, ", "))
+	}
+	return targets, nil
+}
@@ -0,0 +1,91 @@
+package command
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want game.Action
+		// The Command of the UsageError wanted, if the line is no move.
+		usage string
+		// Whether some other error is wanted.
+		bad bool
+	}{
+		{line: "peek", want: game.Action{Kind: game.PeekAction}},
+		{line: "  PEEK  ", want: game.Action{Kind: game.PeekAction}},
+		{line: "challenge", want: game.Action{Kind: game.ChallengeAction}},
+		{line: "cc", want: game.Action{Kind: game.ChallengeAction}},
+		{line: "pass", want: game.Action{Kind: game.NoChallengeAction}},
+		{line: "swap Bob yes", want: game.Action{Kind: game.SwapAction, Target: "Bob", ActuallySwap: true}},
+		{line: "swap #1 n", want: game.Action{Kind: game.SwapAction, Target: "#1"}},
+		{line: "swap Table Card 1 true", want: game.Action{Kind: game.SwapAction, Target: "Table Card 1", ActuallySwap: true}},
+		{line: "claim king", want: game.Action{Kind: game.ClaimAction, Role: role.King}},
+		{line: "claim puppet master", want: game.Action{Kind: game.ClaimAction, Role: role.PuppetMaster}},
+		{line: "", usage: ""},
+		{line: "dance", usage: ""},
+		{line: "swap Bob", usage: "swap"},
+		{line: "claim", usage: "claim"},
+		{line: "peek Bob", usage: "peek"},
+		{line: "swap Bob maybe", bad: true},
+		{line: "claim kaiser", bad: true},
+	}
+
+	for _, test := range tests {
+		got, err := Parse(test.line)
+		var usageErr *UsageError
+		switch {
+		case test.bad:
+			if err == nil || errors.As(err, &usageErr) {
+				t.Errorf("Parse(%q) = %s, %v; want an error other than usage", test.line, got, err)
+			}
+		case test.want == game.Action{}:
+			if !errors.As(err, &usageErr) || usageErr.Command != test.usage {
+				t.Errorf("Parse(%q) = %s, %v; want usage of %q", test.line, got, err, test.usage)
+			}
+		case err != nil || got != test.want:
+			t.Errorf("Parse(%q) = %s, %v; want %s", test.line, got, err, test.want)
+		}
+	}
+}
+
+func TestParseAnswer(t *testing.T) {
+	swappables := game.Prompt{Kind: game.ChooseSwappables, Num: 2, Choices: []string{"Bob", "#0", "#1"}}
+	tests := []struct {
+		prompt game.Prompt
+		line   string
+		want   []string
+	}{
+		{game.Prompt{Kind: game.ChooseBoolean, Num: 1}, "yes", []string{"true"}},
+		{game.Prompt{Kind: game.ChooseBoolean, Num: 1}, "N", []string{"false"}},
+		{game.Prompt{Kind: game.ChooseBoolean, Num: 1}, "false", []string{"false"}},
+		{game.Prompt{Kind: game.ChooseBoolean, Num: 1}, "maybe", nil},
+		{game.Prompt{Kind: game.ChooseBoolean, Num: 1}, "yes no", nil},
+		{game.Prompt{Kind: game.ChooseRole, Num: 1}, "puppet", []string{"Puppet", "Master"}},
+		{game.Prompt{Kind: game.ChooseRole, Num: 1}, "judge", []string{"Judge"}},
+		{game.Prompt{Kind: game.ChooseRole, Num: 1}, "", nil},
+		{swappables, "Bob #1", []string{"Bob", "#1"}},
+		{swappables, "table card 0 Bob", []string{"table card 0", "Bob"}},
+		{swappables, "Bob", nil},
+		{game.Prompt{Kind: game.ChoosePlayers, Num: 1, Choices: []string{"Bob", "Carol"}}, "carol", []string{"carol"}},
+	}
+
+	for _, test := range tests {
+		got, err := ParseAnswer(test.prompt, test.line)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("ParseAnswer(%s, %q) = %q, want an error", test.prompt.Kind, test.line, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseAnswer(%s, %q) = %q, %v; want %q", test.prompt.Kind, test.line, got, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// Package config reads a game's setup from a JSON file, such as
+//
+//	{
+//		"players": ["Alice", "Bob", "Carol", "Dave"],
+//		"roles": {"King": 1, "Queen": 2, "Judge": 1, "Peasant": 2},
+//		"seed": 42,
+//		"rules": {"winning_coins": 15, "opening_reveal": false},
+//		"output": "json"
+//	}
+//
+// Instead of "roles", a "preset" such as "starter" may be named. Rules not
+// given are the official ones. The output is "text" unless it is "json".
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/petertseng/mascarade/format"
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/output"
+	"github.com/petertseng/mascarade/role"
+)
+
+// Config is a game's setup.
+type Config struct {
+	Players []string       `json:"players"`
+	Roles   map[string]int `json:"roles"`
+	Preset  string         `json:"preset"`
+	// If not given, the seating and the deal are different every time.
+	Seed   *int64     `json:"seed"`
+	Rules  game.Rules `json:"rules"`
+	Output string     `json:"output"`
+}
+
+// A FieldError is a problem with one field of a Config, such as "players[2]" or "rules.fine".
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors are problems with several fields of a Config at once, such as
+// every rule that is wrong.
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap lets errors.Is and errors.As look at every field's problem.
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Load reads a Config from a file.
+func Load(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, err
+	}
+	defer f.Close()
+
+	c, err := Parse(f)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %s", path, err)
+	}
+	return c, nil
+}
+
+// Parse reads a Config from r, and makes sure a game can be made from it.
+func Parse(r io.Reader) (Config, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Config{}, err
+	}
+
+	c := Config{Rules: game.OfficialRules(), Output: "text"}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return Config{}, fmt.Errorf("line %d: %s", line(data, syntaxErr.Offset), err)
+		case errors.As(err, &typeErr) && typeErr.Field != "":
+			return Config{}, &FieldError{Field: typeErr.Field, Err: fmt.Errorf("must be a %s, not a %s", typeErr.Type, typeErr.Value)}
+		}
+		return Config{}, err
+	}
+
+	if _, err := c.Builder(); err != nil {
+		return Config{}, err
+	}
+	if _, err := c.Formatter(ioutil.Discard); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
+
+func line(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// Builder makes a GameBuilder set up as c says.
+func (c Config) Builder() (game.GameBuilder, error) {
+	gameBuilder := game.NewBuilder()
+
+	for i, name := range c.Players {
+		if err := gameBuilder.AddPlayer(name); err != nil {
+			return gameBuilder, &FieldError{Field: fmt.Sprintf("players[%d]", i), Err: err}
+		}
+	}
+
+	if c.Preset != "" && len(c.Roles) > 0 {
+		return gameBuilder, &FieldError{Field: "preset", Err: fmt.Errorf("can't be given along with roles")}
+	}
+	if c.Preset != "" {
+		if err := gameBuilder.UsePreset(c.Preset); err != nil {
+			return gameBuilder, &FieldError{Field: "preset", Err: err}
+		}
+	}
+
+	// Go through the roles in order so that the first bad one is always the one reported.
+	names := make([]string, 0, len(c.Roles))
+	for name := range c.Roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := role.FromName(name); err != nil {
+			return gameBuilder, &FieldError{Field: "roles." + name, Err: err}
+		}
+		if err := gameBuilder.AddRoleCopies(name, c.Roles[name]); err != nil {
+			return gameBuilder, &FieldError{Field: "roles." + name, Err: err}
+		}
+	}
+
+	if err := gameBuilder.SetRules(c.Rules); err != nil {
+		var rulesErr game.RulesError
+		if !errors.As(err, &rulesErr) {
+			return gameBuilder, &FieldError{Field: "rules", Err: err}
+		}
+		errs := make(FieldErrors, len(rulesErr))
+		for i, ruleErr := range rulesErr {
+			errs[i] = &FieldError{Field: "rules." + ruleErr.Params["field"], Err: ruleErr}
+		}
+		return gameBuilder, errs
+	}
+
+	if c.Seed != nil {
+		gameBuilder.SetSeed(*c.Seed)
+	}
+
+	// Catch problems with the number of players or cards now, rather than when the game is made.
+	check := gameBuilder
+	check.SetSeed(0)
+	if _, err := check.MakeGameWithFormatter(format.NewNop()); err != nil {
+		var ruleErr *game.RuleError
+		if errors.As(err, &ruleErr) && ruleErr.Code == game.CodePlayerCount {
+			return gameBuilder, &FieldError{Field: "players", Err: err}
+		}
+		return gameBuilder, &FieldError{Field: "roles", Err: err}
+	}
+	return gameBuilder, nil
+}
+
+// Formatter makes the Formatter c asks for, writing to w.
+func (c Config) Formatter(w io.Writer) (format.Formatter, error) {
+	switch c.Output {
+	case "", "text":
+		return format.NewText(output.NewPrefixed(w)), nil
+	case "json":
+		return format.NewJSON(w), nil
+	}
+	return nil, &FieldError{Field: "output", Err: fmt.Errorf("must be text or json, not %s", c.Output)}
+}
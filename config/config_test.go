@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const players = `"players": ["Alice", "Bob", "Carol", "Dave"]`
+const roles = `"roles": {"King": 1, "Queen": 1, "Judge": 1, "Bishop": 1, "Thief": 1, "Witch": 1}`
+
+func TestParseFieldErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		json string
+		// The fields wanted in a FieldError or FieldErrors, or none if the config is good.
+		fields []string
+	}{
+		{"good", `{` + players + `, ` + roles + `}`, nil},
+		{"preset", `{` + players + `, "preset": "starter"}`, nil},
+		{"bad player", `{"players": ["Alice", "Bob", "Carol", "#1"], ` + roles + `}`, []string{"players[3]"}},
+		{"same player", `{"players": ["Alice", "Bob", "Carol", "alice"], ` + roles + `}`, []string{"players[3]"}},
+		{"too few players", `{"players": ["Alice"], ` + roles + `}`, []string{"players"}},
+		{"preset and roles", `{` + players + `, ` + roles + `, "preset": "starter"}`, []string{"preset"}},
+		{"no such preset", `{` + players + `, "preset": "rulebook"}`, []string{"preset"}},
+		{"inexact role", `{` + players + `, "roles": {"king": 1, "Queen": 1, "Judge": 1, "Bishop": 1, "Thief": 1, "Witch": 1}}`, []string{"roles.king"}},
+		{"too few roles", `{` + players + `, "roles": {"King": 1}}`, []string{"roles"}},
+		{"one rule", `{` + players + `, ` + roles + `, "rules": {"widow_coins": 0}}`, []string{"rules.widow_coins"}},
+		{"two rules", `{` + players + `, ` + roles + `, "rules": {"starting_coins": 0, "swap_turns": 1000}}`, []string{"rules.starting_coins", "rules.swap_turns"}},
+		{"wrong type", `{` + players + `, ` + roles + `, "seed": "lots"}`, []string{"seed"}},
+		{"no such output", `{` + players + `, ` + roles + `, "output": "xml"}`, []string{"output"}},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(strings.NewReader(test.json))
+		if test.fields == nil {
+			if err != nil {
+				t.Errorf("%s: %s", test.desc, err)
+			}
+			continue
+		}
+
+		var fields []string
+		var fieldErrs FieldErrors
+		var fieldErr *FieldError
+		switch {
+		case errors.As(err, &fieldErrs):
+			for _, e := range fieldErrs {
+				fields = append(fields, e.Field)
+			}
+		case errors.As(err, &fieldErr):
+			fields = []string{fieldErr.Field}
+		default:
+			t.Errorf("%s: got %v, want a FieldError", test.desc, err)
+			continue
+		}
+		if !reflect.DeepEqual(fields, test.fields) {
+			t.Errorf("%s: bad fields %q, want %q", test.desc, fields, test.fields)
+		}
+	}
+}
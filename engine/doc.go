@@ -0,0 +1,68 @@
+/*
+Package engine lets a bot running in another process, written in any
+language, take a seat at a game.
+
+The engine process reads messages on its standard input and writes replies on
+its standard output. Every message and every reply is a single line holding a
+JSON object. Roles are written by name, such as "King" or "Puppet Master", and
+table cards are named #0, #1, #2... as they would be chosen as targets.
+Anything an engine writes to its standard error is passed through untouched.
+
+Each message has a "type". Only hello, act and prompt need a reply.
+
+	{"type": "hello", "protocol": 1, "seat": "Alice"}
+
+The first message, naming the player the engine is seated as.
+The engine replies with its own name:
+
+	{"name": "My Engine 1.0"}
+
+	{"type": "swapped", "a": "Alice", "b": "#0"}
+
+The cards of a and b may have been exchanged: someone swapped (or not), or
+used the Fool's or the Spy's power.
+
+	{"type": "shown", "whose": "Bob", "role": "King"}
+
+The engine's player has seen that the card of whose is role, whether
+privately (by peeking, or with the Spy) or because it was revealed to all.
+
+	{"type": "act", "view": {...}}
+
+It is the engine's turn, or it must decide whether to challenge a claim; the
+view has a non-empty "claimant" in that case. The view is a game.View: "self",
+"players" in seating order (each with "name", "coins", "revealed" and
+"last_revealed"), "table_cards", "roles", "deck" (how many cards of each role
+there are), "rules" (a game.Rules), "turn_count", "courthouse", "active", and
+while a claim is waiting, "claimant", "claimed_role" and "challengers". The
+engine replies with one of:
+
+	{"action": "swap", "target": "Bob", "swap": true}
+	{"action": "peek"}
+	{"action": "claim", "role": "King"}
+	{"action": "challenge"}
+	{"action": "pass"}
+
+	{"type": "prompt", "view": {...}, "prompt": {...}}
+
+The engine's player must answer a question while a power is being used. The
+prompt is a game.Prompt: "player", "power", "num", and a "kind" of swappables,
+players or coin_owner (choose "num" different names from "choices"), role
+(name any role, as the target of the Inquisitor), or boolean (whether to
+actually swap, "true" or "false"). The engine replies with its answer:
+
+	{"answer": ["Bob", "#1"]}
+
+	{"type": "error", "code": "must_swap_early", "params": {...}, "message": "..."}
+
+The game refused the engine's last reply to act or prompt, which will be
+sent again. The code and params are those of a game.RuleError, and are left
+out when the reply was not understood at all. An engine that makes 3 bad
+replies in a game, or takes more than 10 seconds to reply, is given up on,
+and random moves are made for it instead.
+
+	{"type": "quit"}
+
+The game is over or the engine is no longer wanted, and it should exit.
+*/
+package engine
@@ -0,0 +1,237 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/petertseng/mascarade/bot"
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+// An Engine is a bot that is played by another process, speaking the protocol
+// described in the package documentation.
+//
+// Every move or answer the game refuses is sent back to the engine as an
+// error, and it is asked again. If the engine stops following the protocol,
+// or makes maxBadReplies bad replies, the Engine makes random legal moves for
+// the rest of the game so that the game can go on; Err tells what went wrong.
+// So does an engine that takes longer than Timeout to reply.
+type Engine struct {
+	Name    string
+	Timeout time.Duration
+
+	enc     *json.Encoder
+	replies chan read
+	done    chan struct{}
+	cmd     *exec.Cmd
+	in      io.Closer
+
+	fallback   bot.Bot
+	badReplies int
+	err        error
+}
+
+// How many bad replies an engine may make in a game before it is given up on.
+const maxBadReplies = 3
+
+// How long New gives an engine to reply to each message.
+const defaultTimeout = 10 * time.Second
+
+// A read is a reply read from an engine, not yet decoded, or why none could be.
+type read struct {
+	raw json.RawMessage
+	err error
+}
+
+// Start runs an engine program, seated as the named player.
+func Start(seat string, path string, args ...string) (*Engine, error) {
+	cmd := exec.Command(path, args...)
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	e, err := New(seat, out, in)
+	if err != nil {
+		in.Close()
+		cmd.Wait()
+		return nil, err
+	}
+	e.cmd = cmd
+	e.in = in
+	return e, nil
+}
+
+// New speaks to an engine by writing messages to w and reading its replies
+// from r, such as one at the other end of a network connection.
+func New(seat string, r io.Reader, w io.Writer) (*Engine, error) {
+	e := &Engine{
+		Timeout:  defaultTimeout,
+		enc:      json.NewEncoder(w),
+		replies:  make(chan read),
+		done:     make(chan struct{}),
+		fallback: bot.NewRandom(rand.New(rand.NewSource(time.Now().UnixNano()))),
+	}
+	go e.read(json.NewDecoder(bufio.NewReader(r)))
+
+	var hello reply
+	if err := e.ask(message{Type: "hello", Protocol: protocolVersion, Seat: seat}, &hello); err != nil {
+		close(e.done)
+		return nil, fmt.Errorf("Engine for %s did not say hello: %s", seat, err)
+	}
+	e.Name = hello.Name
+	return e, nil
+}
+
+// Err tells why the engine was given up on, if it was.
+func (e *Engine) Err() error {
+	return e.err
+}
+
+func (e *Engine) send(m message) error {
+	if err := e.enc.Encode(m); err != nil {
+		return fmt.Errorf("Couldn't send %s: %s", m.Type, err)
+	}
+	return nil
+}
+
+// read reads replies until the engine stops following the protocol or is closed.
+func (e *Engine) read(dec *json.Decoder) {
+	for {
+		var rd read
+		rd.err = dec.Decode(&rd.raw)
+		select {
+		case e.replies <- rd:
+		case <-e.done:
+			return
+		}
+		if rd.err != nil {
+			return
+		}
+	}
+}
+
+// ask sends m and reads the reply into r. A reply that is JSON but not a
+// reply is refused, and m is sent again.
+func (e *Engine) ask(m message, r *reply) error {
+	for {
+		if err := e.send(m); err != nil {
+			return err
+		}
+
+		var rd read
+		select {
+		case rd = <-e.replies:
+		case <-time.After(e.Timeout):
+			return fmt.Errorf("No reply to %s after %s", m.Type, e.Timeout)
+		}
+		if rd.err != nil {
+			return fmt.Errorf("Bad reply to %s: %s", m.Type, rd.err)
+		}
+
+		*r = reply{}
+		err := json.Unmarshal(rd.raw, r)
+		if err == nil {
+			return nil
+		}
+		e.Refused(fmt.Errorf("Couldn't understand reply to %s: %s", m.Type, err))
+		if e.err != nil {
+			return e.err
+		}
+	}
+}
+
+func (e *Engine) giveUp(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+// Refused tells the engine why its last reply was refused, and gives up on
+// it if it has made too many bad replies.
+func (e *Engine) Refused(err error) {
+	if e.err != nil {
+		return
+	}
+	e.badReplies++
+	m := message{Type: "error", Message: err.Error()}
+	var ruleErr *game.RuleError
+	if errors.As(err, &ruleErr) {
+		m.Code = string(ruleErr.Code)
+		m.Params = ruleErr.Params
+	}
+	if sendErr := e.send(m); sendErr != nil {
+		e.giveUp(sendErr)
+	} else if e.badReplies >= maxBadReplies {
+		e.giveUp(fmt.Errorf("%d bad replies, the last refused because %s", e.badReplies, err))
+	}
+}
+
+func (e *Engine) Swapped(a, b string) {
+	if e.err == nil {
+		e.giveUp(e.send(message{Type: "swapped", A: a, B: b}))
+	}
+}
+
+func (e *Engine) Shown(whose string, r role.Role) {
+	if e.err == nil {
+		e.giveUp(e.send(message{Type: "shown", Whose: whose, Role: r}))
+	}
+}
+
+func (e *Engine) Act(v game.View) game.Action {
+	for e.err == nil {
+		var r reply
+		if err := e.ask(message{Type: "act", View: &v}, &r); err != nil {
+			e.giveUp(err)
+			break
+		}
+		a, err := r.action()
+		if err == nil {
+			return a
+		}
+		e.Refused(err)
+	}
+	return e.fallback.Act(v)
+}
+
+func (e *Engine) Answer(v game.View, p game.Prompt) []string {
+	if e.err == nil {
+		var r reply
+		err := e.ask(message{Type: "prompt", View: &v, Prompt: &p}, &r)
+		if err == nil {
+			return r.Answer
+		}
+		e.giveUp(err)
+	}
+	return e.fallback.Answer(v, p)
+}
+
+// Close tells the engine to quit, and waits for it to do so if Start ran it.
+func (e *Engine) Close() error {
+	close(e.done)
+	err := e.send(message{Type: "quit"})
+	if e.cmd != nil {
+		e.in.Close()
+		if waitErr := e.cmd.Wait(); err == nil {
+			err = waitErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/petertseng/mascarade/bot"
+	"github.com/petertseng/mascarade/format"
+	"github.com/petertseng/mascarade/game"
+)
+
+// serve runs b as an engine at the other end of a pair of pipes, and
+// connects to it. It tells what Serve returned once the engine is closed.
+func serve(t *testing.T, seat string, b bot.Bot) (*Engine, <-chan error) {
+	toEngine, fromGame := io.Pipe()
+	toGame, fromEngine := io.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve("test", b, toEngine, fromEngine)
+		fromEngine.Close()
+	}()
+
+	e, err := New(seat, toGame, fromGame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e, served
+}
+
+func TestServe(t *testing.T) {
+	gameBuilder := game.NewBuilder()
+	gameBuilder.SetSeed(1)
+	for _, name := range []string{"King", "Queen", "Judge", "Bishop", "Thief", "Witch"} {
+		if err := gameBuilder.AddRole(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	engines := make(map[string]*Engine)
+	served := make(map[string]<-chan error)
+	seats := make(map[string]bot.Bot)
+	for i := 1; i <= 4; i++ {
+		name := fmt.Sprintf("P%d", i)
+		if err := gameBuilder.AddPlayer(name); err != nil {
+			t.Fatal(err)
+		}
+		engines[name], served[name] = serve(t, name, bot.NewKnowing(rand.New(rand.NewSource(int64(i)))))
+		seats[name] = engines[name]
+	}
+
+	table := bot.NewTable(rand.New(rand.NewSource(0)), seats)
+	gameBuilder.SetChoiceGetter(table.Choose)
+	g, err := gameBuilder.MakeGameWithFormatter(table.Formatter(format.NewNop()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Play(&g); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, e := range engines {
+		if e.Name != "test" {
+			t.Errorf("%s's engine is called %q, want %q", name, e.Name, "test")
+		}
+		if err := e.Err(); err != nil {
+			t.Errorf("%s's engine was given up on: %s", name, err)
+		}
+		if err := e.Close(); err != nil {
+			t.Errorf("Closing %s's engine: %s", name, err)
+		}
+		if err := <-served[name]; err != nil {
+			t.Errorf("%s's engine: %s", name, err)
+		}
+	}
+}
+
+// script plays an engine that says hello and then gives replies, one for
+// each message that needs one, reading every message it is sent.
+func script(replies ...string) (io.Reader, io.Writer, <-chan message) {
+	toEngine, fromGame := io.Pipe()
+	toGame, fromEngine := io.Pipe()
+	sent := make(chan message, 100)
+	go func() {
+		dec := json.NewDecoder(bufio.NewReader(toEngine))
+		replies = append([]string{`{"name": "script"}`}, replies...)
+		for {
+			var m message
+			if err := dec.Decode(&m); err != nil {
+				return
+			}
+			sent <- m
+			if m.Type != "hello" && m.Type != "act" && m.Type != "prompt" || len(replies) == 0 {
+				continue
+			}
+			fmt.Fprintln(fromEngine, replies[0])
+			replies = replies[1:]
+		}
+	}()
+	return toGame, fromGame, sent
+}
+
+func view(t *testing.T) game.View {
+	gameBuilder := game.NewBuilder()
+	for _, name := range []string{"King", "Queen", "Judge", "Bishop", "Thief", "Witch"} {
+		gameBuilder.AddRole(name)
+	}
+	for _, name := range []string{"P1", "P2", "P3", "P4"} {
+		gameBuilder.AddPlayer(name)
+	}
+	g, err := gameBuilder.MakeGameWithFormatter(format.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g.View(g.ActivePlayerName())
+}
+
+func TestUndecodableReply(t *testing.T) {
+	r, w, sent := script(`{"action": "claim", "role": "Kinng"}`, `{"action": "swap", "target": "P2", "swap": true}`)
+	e, err := New("P1", r, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := game.Action{Kind: game.SwapAction, Target: "P2", ActuallySwap: true}
+	if got := e.Act(view(t)); got != want {
+		t.Errorf("Got %s, want %s", got, want)
+	}
+	if err := e.Err(); err != nil {
+		t.Errorf("Given up on: %s", err)
+	}
+
+	refused := false
+	for len(sent) > 0 {
+		if m := <-sent; m.Type == "error" {
+			refused = true
+		}
+	}
+	if !refused {
+		t.Error("The engine wasn't told its reply was refused")
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	r, w, _ := script()
+	e, err := New("P1", r, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Timeout = 10 * time.Millisecond
+
+	v := view(t)
+	a := e.Act(v)
+	if e.Err() == nil {
+		t.Error("An engine that never replied wasn't given up on")
+	}
+	legal := false
+	for _, l := range v.LegalActions() {
+		legal = legal || l == a
+	}
+	if !legal {
+		t.Errorf("Made %s, which isn't legal", a)
+	}
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+const protocolVersion = 1
+
+// A message is anything sent to an engine.
+type message struct {
+	Type     string       `json:"type"`
+	Protocol int          `json:"protocol,omitempty"`
+	Seat     string       `json:"seat,omitempty"`
+	A        string       `json:"a,omitempty"`
+	B        string       `json:"b,omitempty"`
+	Whose    string       `json:"whose,omitempty"`
+	Role     role.Role    `json:"role,omitempty"`
+	View     *game.View   `json:"view,omitempty"`
+	Prompt   *game.Prompt `json:"prompt,omitempty"`
+	// Why the engine's last reply was refused.
+	Code    string            `json:"code,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Params  map[string]string `json:"params,omitempty"`
+}
+
+// A reply is anything an engine sends back.
+type reply struct {
+	Name   string    `json:"name,omitempty"`
+	Action string    `json:"action,omitempty"`
+	Target string    `json:"target,omitempty"`
+	Swap   bool      `json:"swap,omitempty"`
+	Role   role.Role `json:"role,omitempty"`
+	Answer []string  `json:"answer,omitempty"`
+}
+
+var actionNames = map[game.ActionKind]string{
+	game.SwapAction:        "swap",
+	game.PeekAction:        "peek",
+	game.ClaimAction:       "claim",
+	game.ChallengeAction:   "challenge",
+	game.NoChallengeAction: "pass",
+}
+
+func replyFor(a game.Action) reply {
+	r := reply{Action: actionNames[a.Kind]}
+	switch a.Kind {
+	case game.SwapAction:
+		r.Target = a.Target
+		r.Swap = a.ActuallySwap
+	case game.ClaimAction:
+		r.Role = a.Role
+	}
+	return r
+}
+
+func (r reply) action() (game.Action, error) {
+	for kind, name := range actionNames {
+		if name == r.Action {
+			return game.Action{Kind: kind, Target: r.Target, ActuallySwap: r.Swap, Role: r.Role}, nil
+		}
+	}
+	return game.Action{}, fmt.Errorf("No such action %q", r.Action)
+}
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/petertseng/mascarade/bot"
+)
+
+// Serve plays as an engine: it reads messages from r and has b reply to them
+// on w, until it is told to quit. It is how a bot written in Go can be run
+// as an engine, and shows how an engine should behave.
+func Serve(name string, b bot.Bot, r io.Reader, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	dec := json.NewDecoder(bufio.NewReader(r))
+	observer, observes := b.(bot.Observer)
+
+	for {
+		var m message
+		if err := dec.Decode(&m); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		var err error
+		switch m.Type {
+		case "hello":
+			if m.Protocol != protocolVersion {
+				return fmt.Errorf("Can't speak protocol %d", m.Protocol)
+			}
+			err = enc.Encode(reply{Name: name})
+		case "swapped":
+			if observes {
+				observer.Swapped(m.A, m.B)
+			}
+		case "shown":
+			if observes {
+				observer.Shown(m.Whose, m.Role)
+			}
+		case "act":
+			if m.View == nil {
+				return fmt.Errorf("No view to act on")
+			}
+			err = enc.Encode(replyFor(b.Act(*m.View)))
+		case "prompt":
+			if m.View == nil || m.Prompt == nil {
+				return fmt.Errorf("No view or prompt to answer")
+			}
+			err = enc.Encode(reply{Answer: b.Answer(*m.View, *m.Prompt)})
+		case "error":
+			if r, ok := b.(bot.Refusable); ok {
+				r.Refused(errors.New(m.Message))
+			}
+		case "quit":
+			return nil
+		default:
+			return fmt.Errorf("No such message type %q", m.Type)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -4,6 +4,16 @@ import (
 	"github.com/petertseng/mascarade/role"
 )
 
+// A CodedError is an error with a stable, machine-readable code and the
+// values its message was made from, such as the rule errors of the game
+// package. Formatters may look for one with errors.As in Error to branch on
+// or translate the error rather than show its message.
+type CodedError interface {
+	error
+	ErrorCode() string
+	ErrorParams() map[string]string
+}
+
 type Formatter interface {
 	YourTurn(player string) error
 	SwapOrNot(swapper, swapee string) error
@@ -28,10 +38,17 @@ type Formatter interface {
 	WinTargetReached(winners []string) error
 	WinBroke(winners, broke []string) error
 
+	SwapOrNotOthers(swapper, first, second string) error
 	TellCard(peeker, whoseCard string, r role.Role) error
+	ShowStartingCard(whose string, r role.Role) error
+	RolesInGame(roles []role.Role) error
 	PromptForRole(player string) error
 	PromptForPlayer(player string, r role.Role, num int, extra string) error
 	PromptForSwap(player string) error
 	PromptForSwappable(player string, r role.Role, num int) error
 	Error(player string, e error) error
+
+	// Hint suggests a move to player alone. Games never give hints; they
+	// are for frontends that advise their players.
+	Hint(player, hint string) error
 }
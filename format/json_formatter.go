@@ -0,0 +1,158 @@
+package format
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/petertseng/mascarade/role"
+)
+
+// NewJSON makes a Formatter that writes every event as one line of JSON, such as
+//
+//	{"event":"swap_or_not","swapper":"Alice","swapee":"Bob"}
+//
+// Events meant for one player only have "private" set to that player's name.
+func NewJSON(w io.Writer) Formatter {
+	return JSONFormatter{enc: json.NewEncoder(w)}
+}
+
+type JSONFormatter struct {
+	enc *json.Encoder
+}
+
+type fields map[string]interface{}
+
+func (jf JSONFormatter) public(event string, f fields) error {
+	f["event"] = event
+	return jf.enc.Encode(f)
+}
+
+func (jf JSONFormatter) private(player, event string, f fields) error {
+	f["private"] = player
+	return jf.public(event, f)
+}
+
+func (jf JSONFormatter) YourTurn(player string) error {
+	return jf.public("your_turn", fields{"player": player})
+}
+
+func (jf JSONFormatter) SwapOrNot(swapper, swapee string) error {
+	return jf.public("swap_or_not", fields{"swapper": swapper, "swapee": swapee})
+}
+
+func (jf JSONFormatter) Peek(peeker string) error {
+	return jf.public("peek", fields{"peeker": peeker})
+}
+
+func (jf JSONFormatter) TellOwnCard(peeker string, r role.Role) error {
+	return jf.private(peeker, "own_card", fields{"role": r})
+}
+
+func (jf JSONFormatter) ClaimRole(claimant string, r role.Role) error {
+	return jf.public("claim", fields{"claimant": claimant, "role": r})
+}
+
+func (jf JSONFormatter) YourTurnToChallenge(player, claimant string, r role.Role) error {
+	return jf.public("your_turn_to_challenge", fields{"player": player, "claimant": claimant, "role": r})
+}
+
+func (jf JSONFormatter) Counterclaim(claimant, original string, r role.Role) error {
+	return jf.public("counterclaim", fields{"claimant": claimant, "original": original, "role": r})
+}
+
+func (jf JSONFormatter) NoCounterclaim(claimant, original string, r role.Role) error {
+	return jf.public("no_counterclaim", fields{"player": claimant, "original": original, "role": r})
+}
+
+func (jf JSONFormatter) NobodyChallenged(claimant string, r role.Role) error {
+	return jf.public("nobody_challenged", fields{"claimant": claimant, "role": r})
+}
+
+func (jf JSONFormatter) GoodClaim(claimant string, r role.Role) error {
+	return jf.public("good_claim", fields{"claimant": claimant, "role": r})
+}
+
+func (jf JSONFormatter) BadClaim(claimant string, had, want role.Role) error {
+	return jf.public("bad_claim", fields{"claimant": claimant, "had": had, "claimed": want})
+}
+
+func (jf JSONFormatter) UsePower(user string, r role.Role) error {
+	return jf.public("use_power", fields{"user": user, "role": r})
+}
+
+func (jf JSONFormatter) GainCoins(gainer string, coins, now uint64) error {
+	return jf.public("gain_coins", fields{"player": gainer, "coins": coins, "now": now})
+}
+
+func (jf JSONFormatter) PayFine(payer string, now uint64) error {
+	return jf.public("pay_fine", fields{"player": payer, "now": now})
+}
+
+func (jf JSONFormatter) PayCoins(giver string, giverCoins, paid uint64, receiver string, receiverCoins uint64) error {
+	return jf.public("pay_coins", fields{"giver": giver, "giver_coins": giverCoins, "paid": paid, "receiver": receiver, "receiver_coins": receiverCoins})
+}
+
+func (jf JSONFormatter) Courthouse(coins uint64) error {
+	return jf.public("courthouse", fields{"coins": coins})
+}
+
+func (jf JSONFormatter) CheaterWins(cheater string) error {
+	return jf.public("cheater_wins", fields{"cheater": cheater})
+}
+
+func (jf JSONFormatter) WinTargetReached(winners []string) error {
+	return jf.public("win_target_reached", fields{"winners": winners})
+}
+
+func (jf JSONFormatter) WinBroke(winners, broke []string) error {
+	return jf.public("win_broke", fields{"winners": winners, "broke": broke})
+}
+
+func (jf JSONFormatter) SwapOrNotOthers(swapper, first, second string) error {
+	return jf.public("swap_or_not_others", fields{"swapper": swapper, "first": first, "second": second})
+}
+
+func (jf JSONFormatter) TellCard(peeker, whoseCard string, r role.Role) error {
+	return jf.private(peeker, "card", fields{"whose": whoseCard, "role": r})
+}
+
+func (jf JSONFormatter) ShowStartingCard(whose string, r role.Role) error {
+	return jf.public("starting_card", fields{"whose": whose, "role": r})
+}
+
+func (jf JSONFormatter) RolesInGame(roles []role.Role) error {
+	return jf.public("roles_in_game", fields{"roles": roles})
+}
+
+func (jf JSONFormatter) PromptForRole(player string) error {
+	return jf.private(player, "prompt_for_role", fields{})
+}
+
+func (jf JSONFormatter) PromptForPlayer(player string, r role.Role, num int, extra string) error {
+	return jf.private(player, "prompt_for_player", fields{"role": r, "num": num, "extra": extra})
+}
+
+func (jf JSONFormatter) PromptForSwap(player string) error {
+	return jf.private(player, "prompt_for_swap", fields{})
+}
+
+func (jf JSONFormatter) PromptForSwappable(player string, r role.Role, num int) error {
+	return jf.private(player, "prompt_for_swappable", fields{"role": r, "num": num})
+}
+
+func (jf JSONFormatter) Error(player string, e error) error {
+	f := fields{"message": e.Error()}
+	var coded CodedError
+	if errors.As(e, &coded) {
+		f["code"] = coded.ErrorCode()
+		if params := coded.ErrorParams(); len(params) > 0 {
+			f["params"] = params
+		}
+	}
+	return jf.private(player, "error", f)
+}
+
+func (jf JSONFormatter) Hint(player, hint string) error {
+	return jf.private(player, "hint", fields{"hint": hint})
+}
@@ -0,0 +1,128 @@
+package format
+
+import (
+	"github.com/petertseng/mascarade/role"
+)
+
+// NewNop makes a Formatter that says nothing at all, for games that nobody is watching.
+func NewNop() Formatter {
+	return NopFormatter{}
+}
+
+type NopFormatter struct{}
+
+func (NopFormatter) YourTurn(player string) error {
+	return nil
+}
+
+func (NopFormatter) SwapOrNot(swapper, swapee string) error {
+	return nil
+}
+
+func (NopFormatter) Peek(peeker string) error {
+	return nil
+}
+
+func (NopFormatter) TellOwnCard(peeker string, r role.Role) error {
+	return nil
+}
+
+func (NopFormatter) ClaimRole(claimant string, r role.Role) error {
+	return nil
+}
+
+func (NopFormatter) YourTurnToChallenge(player, claimant string, r role.Role) error {
+	return nil
+}
+
+func (NopFormatter) Counterclaim(claimant, original string, r role.Role) error {
+	return nil
+}
+
+func (NopFormatter) NoCounterclaim(claimant, original string, r role.Role) error {
+	return nil
+}
+
+func (NopFormatter) NobodyChallenged(claimant string, r role.Role) error {
+	return nil
+}
+
+func (NopFormatter) GoodClaim(claimant string, r role.Role) error {
+	return nil
+}
+
+func (NopFormatter) BadClaim(claimant string, had, want role.Role) error {
+	return nil
+}
+
+func (NopFormatter) UsePower(user string, r role.Role) error {
+	return nil
+}
+
+func (NopFormatter) GainCoins(gainer string, coins, now uint64) error {
+	return nil
+}
+
+func (NopFormatter) PayFine(gainer string, now uint64) error {
+	return nil
+}
+
+func (NopFormatter) PayCoins(giver string, giverCoins, paid uint64, receiver string, receiverCoins uint64) error {
+	return nil
+}
+
+func (NopFormatter) Courthouse(coins uint64) error {
+	return nil
+}
+
+func (NopFormatter) CheaterWins(cheater string) error {
+	return nil
+}
+
+func (NopFormatter) WinTargetReached(winners []string) error {
+	return nil
+}
+
+func (NopFormatter) WinBroke(winners, broke []string) error {
+	return nil
+}
+
+func (NopFormatter) SwapOrNotOthers(swapper, first, second string) error {
+	return nil
+}
+
+func (NopFormatter) TellCard(peeker, whoseCard string, r role.Role) error {
+	return nil
+}
+
+func (NopFormatter) ShowStartingCard(whose string, r role.Role) error {
+	return nil
+}
+
+func (NopFormatter) RolesInGame(roles []role.Role) error {
+	return nil
+}
+
+func (NopFormatter) PromptForRole(player string) error {
+	return nil
+}
+
+func (NopFormatter) PromptForPlayer(player string, r role.Role, num int, extra string) error {
+	return nil
+}
+
+func (NopFormatter) PromptForSwap(player string) error {
+	return nil
+}
+
+func (NopFormatter) PromptForSwappable(player string, r role.Role, num int) error {
+	return nil
+}
+
+func (NopFormatter) Error(player string, e error) error {
+	return nil
+}
+
+func (NopFormatter) Hint(player, hint string) error {
+	return nil
+}
@@ -0,0 +1,274 @@
+package format
+
+import (
+	"github.com/petertseng/mascarade/role"
+)
+
+// NewTee makes a Formatter that tells every one of fs of everything, in
+// order, stopping at the first that fails.
+func NewTee(fs ...Formatter) Formatter {
+	return TeeFormatter(fs)
+}
+
+type TeeFormatter []Formatter
+
+func (t TeeFormatter) YourTurn(player string) error {
+	for _, f := range t {
+		if err := f.YourTurn(player); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) SwapOrNot(swapper, swapee string) error {
+	for _, f := range t {
+		if err := f.SwapOrNot(swapper, swapee); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) Peek(peeker string) error {
+	for _, f := range t {
+		if err := f.Peek(peeker); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) TellOwnCard(peeker string, r role.Role) error {
+	for _, f := range t {
+		if err := f.TellOwnCard(peeker, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) ClaimRole(claimant string, r role.Role) error {
+	for _, f := range t {
+		if err := f.ClaimRole(claimant, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) YourTurnToChallenge(player, claimant string, r role.Role) error {
+	for _, f := range t {
+		if err := f.YourTurnToChallenge(player, claimant, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) Counterclaim(claimant, original string, r role.Role) error {
+	for _, f := range t {
+		if err := f.Counterclaim(claimant, original, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) NoCounterclaim(claimant, original string, r role.Role) error {
+	for _, f := range t {
+		if err := f.NoCounterclaim(claimant, original, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) NobodyChallenged(claimant string, r role.Role) error {
+	for _, f := range t {
+		if err := f.NobodyChallenged(claimant, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) GoodClaim(claimant string, r role.Role) error {
+	for _, f := range t {
+		if err := f.GoodClaim(claimant, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) BadClaim(claimant string, had, want role.Role) error {
+	for _, f := range t {
+		if err := f.BadClaim(claimant, had, want); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) UsePower(user string, r role.Role) error {
+	for _, f := range t {
+		if err := f.UsePower(user, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) GainCoins(gainer string, coins, now uint64) error {
+	for _, f := range t {
+		if err := f.GainCoins(gainer, coins, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) PayFine(gainer string, now uint64) error {
+	for _, f := range t {
+		if err := f.PayFine(gainer, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) PayCoins(giver string, giverCoins, paid uint64, receiver string, receiverCoins uint64) error {
+	for _, f := range t {
+		if err := f.PayCoins(giver, giverCoins, paid, receiver, receiverCoins); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) Courthouse(coins uint64) error {
+	for _, f := range t {
+		if err := f.Courthouse(coins); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) CheaterWins(cheater string) error {
+	for _, f := range t {
+		if err := f.CheaterWins(cheater); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) WinTargetReached(winners []string) error {
+	for _, f := range t {
+		if err := f.WinTargetReached(winners); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) WinBroke(winners, broke []string) error {
+	for _, f := range t {
+		if err := f.WinBroke(winners, broke); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) SwapOrNotOthers(swapper, first, second string) error {
+	for _, f := range t {
+		if err := f.SwapOrNotOthers(swapper, first, second); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) TellCard(peeker, whoseCard string, r role.Role) error {
+	for _, f := range t {
+		if err := f.TellCard(peeker, whoseCard, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) ShowStartingCard(whose string, r role.Role) error {
+	for _, f := range t {
+		if err := f.ShowStartingCard(whose, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) RolesInGame(roles []role.Role) error {
+	for _, f := range t {
+		if err := f.RolesInGame(roles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) PromptForRole(player string) error {
+	for _, f := range t {
+		if err := f.PromptForRole(player); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) PromptForPlayer(player string, r role.Role, num int, extra string) error {
+	for _, f := range t {
+		if err := f.PromptForPlayer(player, r, num, extra); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) PromptForSwap(player string) error {
+	for _, f := range t {
+		if err := f.PromptForSwap(player); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) PromptForSwappable(player string, r role.Role, num int) error {
+	for _, f := range t {
+		if err := f.PromptForSwappable(player, r, num); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) Error(player string, e error) error {
+	for _, f := range t {
+		if err := f.Error(player, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TeeFormatter) Hint(player, hint string) error {
+	for _, f := range t {
+		if err := f.Hint(player, hint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
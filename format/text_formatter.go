@@ -111,11 +111,30 @@ func (tf TextFormatter) WinBroke(winners, broke []string) error {
 	return err
 }
 
+func (tf TextFormatter) SwapOrNotOthers(swapper, first, second string) error {
+	_, err := tf.out.WritePublic([]byte(fmt.Sprintf("%s swaps (or not) %s with %s.\n", swapper, first, second)))
+	return err
+}
+
 func (tf TextFormatter) TellCard(player, whoseCard string, r role.Role) error {
 	_, err := tf.out.WritePrivate(player, []byte(fmt.Sprintf("%s is the %s.\n", whoseCard, r)))
 	return err
 }
 
+func (tf TextFormatter) ShowStartingCard(whose string, r role.Role) error {
+	_, err := tf.out.WritePublic([]byte(fmt.Sprintf("%s starts as the %s.\n", whose, r)))
+	return err
+}
+
+func (tf TextFormatter) RolesInGame(roles []role.Role) error {
+	names := make([]string, len(roles))
+	for i, r := range roles {
+		names[i] = r.String()
+	}
+	_, err := tf.out.WritePublic([]byte(fmt.Sprintf("The roles in this game are: %s.\n", strings.Join(names, ", "))))
+	return err
+}
+
 func (tf TextFormatter) PromptForRole(player string) error {
 	_, err := tf.out.WritePrivate(player, []byte("Choose a role.\n"))
 	return err
@@ -140,3 +159,8 @@ func (tf TextFormatter) Error(player string, e error) error {
 	_, err := tf.out.WritePrivate(player, []byte(fmt.Sprintf("ERROR: %s\n", e)))
 	return err
 }
+
+func (tf TextFormatter) Hint(player, hint string) error {
+	_, err := tf.out.WritePrivate(player, []byte(fmt.Sprintf("Hint: %s\n", hint)))
+	return err
+}
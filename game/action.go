@@ -0,0 +1,60 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/petertseng/mascarade/role"
+)
+
+// ActionKind says which of the Game's action methods an Action stands for.
+type ActionKind int
+
+const (
+	SwapAction ActionKind = iota
+	PeekAction
+	ClaimAction
+	ChallengeAction
+	NoChallengeAction
+)
+
+// An Action is one move by the player who must act now.
+// Target and ActuallySwap are used only by SwapAction, and Role only by ClaimAction.
+type Action struct {
+	Kind         ActionKind
+	Target       string
+	ActuallySwap bool
+	Role         role.Role
+}
+
+// Perform carries out an Action as if the matching method had been called.
+func (g *Game) Perform(a Action) error {
+	switch a.Kind {
+	case SwapAction:
+		return g.SwapOrNot(a.Target, a.ActuallySwap)
+	case PeekAction:
+		return g.Peek()
+	case ClaimAction:
+		return g.ClaimRole(a.Role.String())
+	case ChallengeAction:
+		return g.Challenge()
+	case NoChallengeAction:
+		return g.NoChallenge()
+	}
+	return fmt.Errorf("Unknown action kind %d", a.Kind)
+}
+
+func (a Action) String() string {
+	switch a.Kind {
+	case SwapAction:
+		return fmt.Sprintf("swap %s %t", a.Target, a.ActuallySwap)
+	case PeekAction:
+		return "peek"
+	case ClaimAction:
+		return fmt.Sprintf("claim %s", a.Role)
+	case ChallengeAction:
+		return "cc"
+	case NoChallengeAction:
+		return "pass"
+	}
+	return fmt.Sprintf("Unknown action kind %d", a.Kind)
+}
@@ -0,0 +1,57 @@
+package game
+
+// checkActor makes sure that actor is the player who must act now.
+func (g *Game) checkActor(actor string) error {
+	if active := g.ActivePlayerName(); actor != active {
+		return newRuleError(CodeWrongActor, map[string]string{"actor": actor, "active": active}, "%s can't act now; it's up to %s", actor, active)
+	}
+	return nil
+}
+
+// SwapOrNotAs is SwapOrNot on behalf of actor, who must be the player who must act now.
+func (g *Game) SwapOrNotAs(actor, target string, actuallySwap bool) error {
+	if err := g.checkActor(actor); err != nil {
+		return err
+	}
+	return g.SwapOrNot(target, actuallySwap)
+}
+
+// PeekAs is Peek on behalf of actor, who must be the player who must act now.
+func (g *Game) PeekAs(actor string) error {
+	if err := g.checkActor(actor); err != nil {
+		return err
+	}
+	return g.Peek()
+}
+
+// ClaimRoleAs is ClaimRole on behalf of actor, who must be the player who must act now.
+func (g *Game) ClaimRoleAs(actor, roleName string) error {
+	if err := g.checkActor(actor); err != nil {
+		return err
+	}
+	return g.ClaimRole(roleName)
+}
+
+// ChallengeAs is Challenge on behalf of actor, who must be the player who must act now.
+func (g *Game) ChallengeAs(actor string) error {
+	if err := g.checkActor(actor); err != nil {
+		return err
+	}
+	return g.Challenge()
+}
+
+// NoChallengeAs is NoChallenge on behalf of actor, who must be the player who must act now.
+func (g *Game) NoChallengeAs(actor string) error {
+	if err := g.checkActor(actor); err != nil {
+		return err
+	}
+	return g.NoChallenge()
+}
+
+// PerformAs is Perform on behalf of actor, who must be the player who must act now.
+func (g *Game) PerformAs(actor string, a Action) error {
+	if err := g.checkActor(actor); err != nil {
+		return err
+	}
+	return g.Perform(a)
+}
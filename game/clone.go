@@ -0,0 +1,91 @@
+package game
+
+import (
+	"github.com/petertseng/mascarade/format"
+	"github.com/petertseng/mascarade/player"
+	"github.com/petertseng/mascarade/role"
+)
+
+// Clone makes an independent copy of the game, which tells f of what happens
+// in it and asks choiceGetter for answers to power prompts.
+// The copy never reads from standard input.
+func (g *Game) Clone(f format.Formatter, choiceGetter ChoiceGetter) *Game {
+	return g.clone(f, choiceGetter, nil)
+}
+
+// Redealt is like Clone, but in the copy, cards are other roles, naming them
+// as SwapOrNot would. It is so that a player searching ahead can fill in the
+// cards they can't see with guesses.
+func (g *Game) Redealt(f format.Formatter, choiceGetter ChoiceGetter, cards map[string]role.Role) (*Game, error) {
+	roles := make(map[string]role.Role, len(cards))
+	for name, r := range cards {
+		swappable, err := g.ResolveSwappable(name)
+		if err != nil {
+			return nil, err
+		}
+		roles[swappable.Name()] = r
+	}
+	return g.clone(f, choiceGetter, roles), nil
+}
+
+// clone copies the game, dealing the cards named in roles, by their own names, anew.
+func (g *Game) clone(f format.Formatter, choiceGetter ChoiceGetter, roles map[string]role.Role) *Game {
+	c := &Game{
+		roles:              g.roles,
+		players:            make(map[string]*player.Player, len(g.players)),
+		playerOrder:        make([]*player.Player, len(g.playerOrder)),
+		tableCards:         make([]*player.TableCard, len(g.tableCards)),
+		currentPlayerIndex: g.currentPlayerIndex,
+
+		choiceGetter: choiceGetter,
+		format:       f,
+		rules:        g.rules,
+
+		turnCount:  g.turnCount,
+		courthouse: g.courthouse,
+
+		claim:            g.claim,
+		claimPlayerIndex: g.claimPlayerIndex,
+		claimedRole:      g.claimedRole,
+	}
+
+	dealt := func(s player.Swappable) role.Role {
+		if r, ok := roles[s.Name()]; ok {
+			return r
+		}
+		return s.Role()
+	}
+	for i, p := range g.playerOrder {
+		c.playerOrder[i] = p.Clone(dealt(p))
+		c.players[p.Name()] = c.playerOrder[i]
+	}
+	for i, tc := range g.tableCards {
+		c.tableCards[i] = tc.Clone(dealt(tc))
+	}
+
+	copyPlayers := func(ps []*player.Player) []*player.Player {
+		copied := make([]*player.Player, len(ps))
+		for i, p := range ps {
+			copied[i] = c.players[p.Name()]
+		}
+		return copied
+	}
+	c.deadPlayers = copyPlayers(g.deadPlayers)
+	c.otherClaimants = copyPlayers(g.otherClaimants)
+	c.cheatWinners = copyPlayers(g.cheatWinners)
+	c.winners = append([]string(nil), g.winners...)
+	c.winCondition = g.winCondition
+
+	return c
+}
+
+// Card tells which role a card really is, naming it as SwapOrNot would.
+// Nobody playing the game should be shown this; it is for copies made with
+// Redealt, and for looking back on a finished game.
+func (g *Game) Card(name string) (role.Role, error) {
+	swappable, err := g.ResolveSwappable(name)
+	if err != nil {
+		return role.NoSuchRole, err
+	}
+	return swappable.Role(), nil
+}
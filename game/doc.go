@@ -0,0 +1,26 @@
+/*
+Package game plays Mascarade.
+
+A game is set up with a GameBuilder: 2 to 13 players, and roles added one by
+one (AddRole, or AddRoleCopies for house variants with two Queens), chosen by
+a preset with UsePreset, or at random around some required roles with
+UseRandomRoles. Roles beyond the players' cards go on the table, and there
+are at least 6 cards in all. The numbers the game is played with are a
+Rules, which SetRules changes for house rules.
+
+Players and table cards are chosen as targets by name: table cards as #0,
+#1... or Table Card 0, Table Card 1..., and players in any case, or cut
+short if no other player's name starts the same way. Roles may be named as
+role.FromString reads them.
+
+Networked frontends should use the methods ending in As, such as
+SwapOrNotAs, which name the player acting and fail with ErrWrongActor if
+somebody else must act. Moves the rules don't allow fail with a *RuleError,
+whose Code and Params stay the same whatever the wording of its message;
+compare it with errors.Is against the sentinels such as ErrMustSwapEarly.
+The same errors reach the Formatter's Error, where they are
+format.CodedErrors. LegalActions lists every move that would be accepted.
+
+A Game is not safe for concurrent use; the package lobby hosts many at once.
+*/
+package game
@@ -0,0 +1,146 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/petertseng/mascarade/role"
+)
+
+// A Code names a kind of RuleError. Codes stay the same whatever the wording
+// of the message, so clients can branch on them or translate them.
+type Code string
+
+const (
+	// A claim must be challenged or not first. Params: claimant, role.
+	CodeMustRespond Code = "must_respond"
+	// Only swapping (or not) is allowed in the first few turns. Params: turns.
+	CodeMustSwapEarly Code = "must_swap_early"
+	// Only swapping (or not) is allowed right after revealing. Params: player.
+	CodeMustSwapRevealed Code = "must_swap_revealed"
+	// Params: player.
+	CodeSwapWithSelf Code = "swap_with_self"
+	// Params: role.
+	CodeNeverAnnounced Code = "never_announced"
+	// Params: role.
+	CodeRoleNotInGame Code = "role_not_in_game"
+	// Challenging or not with no claim to respond to.
+	CodeNoClaim Code = "no_claim"
+	// Params: name.
+	CodeNoSuchPlayer Code = "no_such_player"
+	// Params: name, tablecards.
+	CodeNoSuchTableCard Code = "no_such_table_card"
+	// More than one player goes by the name. Params: name, and matches separated by commas.
+	CodeAmbiguousTarget Code = "ambiguous_target"
+	// No role, or more than one, goes by the name. Params: name, and
+	// suggestions if there are roles it might have meant, separated by commas.
+	CodeNoSuchRole Code = "no_such_role"
+	// Params: answer.
+	CodeNotBoolean Code = "not_boolean"
+	// The same choice was made twice. Params: name, num.
+	CodeDuplicateChoice Code = "duplicate_choice"
+	// Params: num.
+	CodeWrongNumberOfChoices Code = "wrong_number_of_choices"
+	// Params: actor, active.
+	CodeWrongActor Code = "wrong_actor"
+	// Fewer roles, counting both Peasants, than the rules call for. Params: roles, players, cards.
+	CodeNotEnoughRoles Code = "not_enough_roles"
+	// Params: players.
+	CodePlayerCount Code = "player_count"
+	// Params: name.
+	CodeBadPlayerName Code = "bad_player_name"
+	// Params: name.
+	CodeDuplicatePlayer Code = "duplicate_player"
+	// Params: name.
+	CodeNoSuchPreset Code = "no_such_preset"
+	// No set of roles makes exactly the cards needed. Params: cards.
+	CodeCantChooseRoles Code = "cant_choose_roles"
+	// Roles were both added by hand and chosen by a preset.
+	CodeRolesTwice Code = "roles_twice"
+	// Params: role, copies.
+	CodeBadCopies Code = "bad_copies"
+	// A game can't be played by the rules. Params: field.
+	CodeBadRule Code = "bad_rule"
+)
+
+// A RuleError is a move or setup the rules don't allow, or an answer that
+// can't be understood. Params hold the names and numbers in the message.
+// errors.Is matches a RuleError to any other of the same Code, such as the sentinels below.
+type RuleError struct {
+	Code   Code
+	Params map[string]string
+	msg    string
+}
+
+func newRuleError(code Code, params map[string]string, format string, a ...interface{}) *RuleError {
+	return &RuleError{Code: code, Params: params, msg: fmt.Sprintf(format, a...)}
+}
+
+func (e *RuleError) Error() string {
+	return e.msg
+}
+
+// ErrorCode and ErrorParams make a RuleError a format.CodedError.
+func (e *RuleError) ErrorCode() string {
+	return string(e.Code)
+}
+
+func (e *RuleError) ErrorParams() map[string]string {
+	return e.Params
+}
+
+func (e *RuleError) Is(target error) bool {
+	t, ok := target.(*RuleError)
+	return ok && t.Code == e.Code
+}
+
+func sentinel(code Code) *RuleError {
+	return &RuleError{Code: code, msg: string(code)}
+}
+
+// Sentinels to compare errors against with errors.Is.
+var (
+	ErrMustRespond          = sentinel(CodeMustRespond)
+	ErrMustSwapEarly        = sentinel(CodeMustSwapEarly)
+	ErrMustSwapRevealed     = sentinel(CodeMustSwapRevealed)
+	ErrSwapWithSelf         = sentinel(CodeSwapWithSelf)
+	ErrNeverAnnounced       = sentinel(CodeNeverAnnounced)
+	ErrRoleNotInGame        = sentinel(CodeRoleNotInGame)
+	ErrNoClaim              = sentinel(CodeNoClaim)
+	ErrNoSuchPlayer         = sentinel(CodeNoSuchPlayer)
+	ErrNoSuchTableCard      = sentinel(CodeNoSuchTableCard)
+	ErrAmbiguousTarget      = sentinel(CodeAmbiguousTarget)
+	ErrNoSuchRole           = sentinel(CodeNoSuchRole)
+	ErrNotBoolean           = sentinel(CodeNotBoolean)
+	ErrDuplicateChoice      = sentinel(CodeDuplicateChoice)
+	ErrWrongNumberOfChoices = sentinel(CodeWrongNumberOfChoices)
+	ErrWrongActor           = sentinel(CodeWrongActor)
+	ErrNotEnoughRoles       = sentinel(CodeNotEnoughRoles)
+	ErrPlayerCount          = sentinel(CodePlayerCount)
+	ErrBadPlayerName        = sentinel(CodeBadPlayerName)
+	ErrDuplicatePlayer      = sentinel(CodeDuplicatePlayer)
+	ErrNoSuchPreset         = sentinel(CodeNoSuchPreset)
+	ErrCantChooseRoles      = sentinel(CodeCantChooseRoles)
+	ErrRolesTwice           = sentinel(CodeRolesTwice)
+	ErrBadCopies            = sentinel(CodeBadCopies)
+	ErrBadRule              = sentinel(CodeBadRule)
+)
+
+func noSuchPlayer(name string) *RuleError {
+	return newRuleError(CodeNoSuchPlayer, map[string]string{"name": name}, "No such player %s", name)
+}
+
+// noSuchRole explains err, which role.FromString gave for name.
+func noSuchRole(name string, err error) *RuleError {
+	params := map[string]string{"name": name}
+	var nameErr *role.NameError
+	if errors.As(err, &nameErr) && len(nameErr.Suggestions) > 0 {
+		suggestions := make([]string, len(nameErr.Suggestions))
+		for i, r := range nameErr.Suggestions {
+			suggestions[i] = r.String()
+		}
+		params["suggestions"] = strings.Join(suggestions, ",")
+	}
+	return newRuleError(CodeNoSuchRole, params, "%s", err)
+}
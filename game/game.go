@@ -3,16 +3,17 @@ package game
 import (
 	"bufio"
 	"fmt"
-	"strconv"
+	"sort"
 	"strings"
 
 	"github.com/petertseng/mascarade/format"
+	"github.com/petertseng/mascarade/output"
 	"github.com/petertseng/mascarade/player"
 	"github.com/petertseng/mascarade/role"
 )
 
 type GameResolver interface {
-	UserChoice(user string) []string
+	UserChoice(prompt Prompt) []string
 	PlayersOtherThan(string) map[string]*player.Player
 	CoinOwners() map[string]player.CoinOwner
 	CoinOwnersNextTo(string) (player.CoinOwner, player.CoinOwner, error)
@@ -21,10 +22,43 @@ type GameResolver interface {
 	TakeCourthouse() uint64
 	RevealCard(*player.Player)
 	CheaterWins(*player.Player)
+	Rules() Rules
+}
+
+// A ChoiceGetter supplies the words a player answers with when prompted
+// while a power is being used.
+type ChoiceGetter func(prompt Prompt) []string
+
+// WinCondition says how a game was won.
+type WinCondition int
+
+const (
+	NotWon WinCondition = iota
+	// Somebody reached the winning coins, 13 by the official rules.
+	WinTarget
+	// Somebody went broke, so the richest won.
+	WinBroke
+	// The Cheat was used with enough coins.
+	WinCheat
+)
+
+func (wc WinCondition) String() string {
+	switch wc {
+	case NotWon:
+		return "not won"
+	case WinTarget:
+		return "coins"
+	case WinBroke:
+		return "broke"
+	case WinCheat:
+		return "Cheat"
+	}
+	return fmt.Sprintf("Unknown win condition %d", int(wc))
 }
 
 type Game struct {
-	roles              map[role.Role]bool
+	// How many cards of each role are in the game.
+	roles              map[role.Role]int
 	players            map[string]*player.Player
 	playerOrder        []*player.Player
 	tableCards         []*player.TableCard
@@ -32,8 +66,11 @@ type Game struct {
 
 	deadPlayers []*player.Player
 
-	input  *bufio.Reader
-	format format.Formatter
+	input        *bufio.Reader
+	choiceGetter ChoiceGetter
+	format       format.Formatter
+	// Everything told to the whole table, for History. Clones keep none.
+	history *output.Log
 
 	turnCount  uint
 	courthouse uint64
@@ -43,14 +80,34 @@ type Game struct {
 	claimedRole      role.Role
 	otherClaimants   []*player.Player
 
-	cheatWinner *player.Player
+	cheatWinners []*player.Player
 
-	winners []string
+	rules Rules
+
+	winners      []string
+	winCondition WinCondition
 
 	// TODO cemetery
 }
 
 func (g *Game) startGame() {
+	deck := make([]role.Role, 0, len(g.playerOrder)+len(g.tableCards))
+	for r, copies := range g.roles {
+		for i := 0; i < copies; i++ {
+			deck = append(deck, r)
+		}
+	}
+	sort.Slice(deck, func(i, j int) bool { return deck[i] < deck[j] })
+	g.format.RolesInGame(deck)
+
+	if g.rules.OpeningReveal {
+		for _, p := range g.playerOrder {
+			g.format.ShowStartingCard(p.Name(), p.Role())
+		}
+		for _, tc := range g.tableCards {
+			g.format.ShowStartingCard(tc.Name(), tc.Role())
+		}
+	}
 	g.format.YourTurn(g.ActivePlayerName())
 }
 
@@ -113,8 +170,7 @@ func (g *Game) resolveClaim() {
 	// TODO: If using a wildcard power that copied a pair power, a special case here.
 
 	for _, liar := range liars {
-		liar.PayFine()
-		g.courthouse++
+		g.courthouse += liar.PayFine(g.rules.Fine)
 		g.format.PayFine(liar.Name(), liar.Coins())
 	}
 	if len(liars) > 0 {
@@ -137,9 +193,14 @@ func (g *Game) usePower(p *player.Player, r role.Role, numCorrect int) {
 
 func (g *Game) checkVictory() bool {
 	// If anyone is cheater
-	if g.cheatWinner != nil {
-		g.format.CheaterWins(g.cheatWinner.Name())
-		g.winners = []string{g.cheatWinner.Name()}
+	if len(g.cheatWinners) > 0 {
+		winners := make([]string, len(g.cheatWinners))
+		for i, cheater := range g.cheatWinners {
+			g.format.CheaterWins(cheater.Name())
+			winners[i] = cheater.Name()
+		}
+		g.winners = winners
+		g.winCondition = WinCheat
 		return true
 	}
 
@@ -155,21 +216,22 @@ func (g *Game) checkVictory() bool {
 		}
 	}
 
-	// Someone's at 13, all such players win
-	if highestCoins >= 13 {
+	// Someone's at the winning coins, all such players win
+	if highestCoins >= g.rules.WinningCoins {
 		winners := make([]string, 0)
 		for _, player := range g.players {
-			if player.Coins() >= 13 {
+			if player.Coins() >= g.rules.WinningCoins {
 				winners = append(winners, player.Name())
 			}
 		}
 		g.format.WinTargetReached(winners)
 		g.winners = winners
+		g.winCondition = WinTarget
 		return true
 	}
 
 	// Someone's broke, the richest players win
-	if len(zeroCoins) > 0 {
+	if len(zeroCoins) > 0 && g.rules.BrokeEndsGame {
 		richest := make([]string, 0)
 		for _, player := range g.players {
 			if player.Coins() == highestCoins {
@@ -178,6 +240,7 @@ func (g *Game) checkVictory() bool {
 		}
 		g.format.WinBroke(richest, zeroCoins)
 		g.winners = richest
+		g.winCondition = WinBroke
 		return true
 	}
 
@@ -200,13 +263,10 @@ func (g *Game) AnnouncingPlayerName() string {
 
 func (g *Game) Peek() error {
 	if g.claim {
-		return fmt.Errorf("You must respond to %s's claim of %s", g.AnnouncingPlayerName(), g.claimedRole)
-	}
-	if g.turnCount < 4 {
-		return fmt.Errorf("For the first four turns, you must swap (or not)")
+		return g.mustRespond()
 	}
-	if g.activePlayer().LastRevealed() == g.turnCount-1 {
-		return fmt.Errorf("Because you revealed your card on the previous turn, you must swap (or not)")
+	if err := g.mustSwap(); err != nil {
+		return err
 	}
 
 	g.format.Peek(g.ActivePlayerName())
@@ -219,16 +279,17 @@ func (g *Game) Peek() error {
 
 func (g *Game) SwapOrNot(target string, actuallySwap bool) error {
 	if g.claim {
-		return fmt.Errorf("You must respond to %s's claim of %s", g.AnnouncingPlayerName(), g.claimedRole)
+		return g.mustRespond()
 	}
 
-	swappable, err := g.ResolveSwappable(target)
+	// Nobody may swap with themselves, so a name that could mean the active player or another means the other.
+	swappable, err := g.resolveSwappable(target, g.ActivePlayerName())
 	if err != nil {
 		return err
 	}
 
 	if swappable == g.activePlayer() {
-		return fmt.Errorf("You can't swap with yourself, %s", g.ActivePlayerName())
+		return newRuleError(CodeSwapWithSelf, map[string]string{"player": g.ActivePlayerName()}, "You can't swap with yourself, %s", g.ActivePlayerName())
 	}
 
 	if actuallySwap {
@@ -244,25 +305,22 @@ func (g *Game) SwapOrNot(target string, actuallySwap bool) error {
 
 func (g *Game) ClaimRole(roleName string) error {
 	if g.claim {
-		return fmt.Errorf("You must respond to %s's claim of %s", g.AnnouncingPlayerName(), g.claimedRole)
-	}
-	if g.turnCount < 4 {
-		return fmt.Errorf("For the first four turns, you must swap (or not)")
+		return g.mustRespond()
 	}
-	if g.activePlayer().LastRevealed() == g.turnCount-1 {
-		return fmt.Errorf("Because you revealed your card on the previous turn, you must swap (or not)")
+	if err := g.mustSwap(); err != nil {
+		return err
 	}
 
 	roleClaimed, err := role.FromString(roleName)
 	if err != nil {
-		return err
+		return noSuchRole(roleName, err)
 	}
 
 	if !roleClaimed.CanAnnounce() {
-		return fmt.Errorf("The %s can never be announced", roleClaimed)
+		return newRuleError(CodeNeverAnnounced, map[string]string{"role": roleClaimed.String()}, "The %s can never be announced", roleClaimed)
 	}
-	if !g.roles[roleClaimed] {
-		return fmt.Errorf("The %s is not in this game", roleClaimed)
+	if g.roles[roleClaimed] == 0 {
+		return newRuleError(CodeRoleNotInGame, map[string]string{"role": roleClaimed.String()}, "The %s is not in this game", roleClaimed)
 	}
 
 	g.claim = true
@@ -275,9 +333,14 @@ func (g *Game) ClaimRole(roleName string) error {
 	return nil
 }
 
+func (g *Game) mustRespond() error {
+	return newRuleError(CodeMustRespond, map[string]string{"claimant": g.AnnouncingPlayerName(), "role": g.claimedRole.String()},
+		"You must respond to %s's claim of %s", g.AnnouncingPlayerName(), g.claimedRole)
+}
+
 func (g *Game) NoChallenge() error {
 	if !g.claim {
-		return fmt.Errorf("No role has been announced")
+		return newRuleError(CodeNoClaim, nil, "No role has been announced")
 	}
 
 	g.format.NoCounterclaim(g.ActivePlayerName(), g.AnnouncingPlayerName(), g.claimedRole)
@@ -289,7 +352,7 @@ func (g *Game) NoChallenge() error {
 
 func (g *Game) Challenge() error {
 	if !g.claim {
-		return fmt.Errorf("No role has been announced")
+		return newRuleError(CodeNoClaim, nil, "No role has been announced")
 	}
 
 	g.format.Counterclaim(g.ActivePlayerName(), g.AnnouncingPlayerName(), g.claimedRole)
@@ -300,32 +363,50 @@ func (g *Game) Challenge() error {
 	return nil
 }
 
-func (g *Game) resolvePlayer(name string) (*player.Player, error) {
-	player, ok := g.players[name]
-	if !ok {
-		return nil, fmt.Errorf("No such player %s", name)
+// tableCardName is how a table card is named when choosing it as a target.
+func tableCardName(index int) string {
+	return fmt.Sprintf("#%d", index)
+}
+
+// TargetName gives the name by which a card mentioned in a Formatter event
+// would be chosen as a target: a table card's name becomes #0, #1, #2...
+func TargetName(name string) string {
+	var index int
+	if n, err := fmt.Sscanf(name, "Table Card %d", &index); err == nil && n == 1 {
+		return tableCardName(index)
 	}
-	return player, nil
+	return name
 }
 
+// ResolveSwappable finds the player or table card named, as described at the top of target.go.
 func (g *Game) ResolveSwappable(name string) (player.Swappable, error) {
-	// If it's a table card...
-	if name[0] == '#' {
-		index, err := strconv.ParseInt(name[1:], 0, 64)
-		if err != nil {
-			return nil, fmt.Errorf("No such player %s: %s", name, err)
-		}
-		if index >= int64(len(g.tableCards)) {
-			return nil, fmt.Errorf("There are only %d table cards, so #%d is invalid", len(g.tableCards), index)
-		}
+	return g.resolveSwappable(name, "")
+}
+
+// resolveSwappable finds the player or table card named, taking a name that
+// could be either unchoosable or another player to mean the other player.
+func (g *Game) resolveSwappable(name, unchoosable string) (player.Swappable, error) {
+	// Names are most often exact, as when bots use them, so look for those first.
+	if p, ok := g.players[name]; ok {
+		return p, nil
+	}
+	if index, ok := parseTableCard(name); ok && index >= 0 && index < len(g.tableCards) {
 		return g.tableCards[index], nil
 	}
 
-	// Otherwise, it's a player
-	return g.resolvePlayer(name)
+	swappables := g.SwappablesOtherThan("")
+	target, err := resolveTarget(name, sortedNames(swappables), unchoosable)
+	if err != nil {
+		return nil, err
+	}
+	return swappables[target], nil
 }
 
-func (g *Game) UserChoice(user string) []string {
+func (g *Game) UserChoice(prompt Prompt) []string {
+	if g.choiceGetter != nil {
+		return g.choiceGetter(prompt)
+	}
+
 	for {
 		str, err := g.input.ReadString('\n')
 		if err != nil {
@@ -358,8 +439,7 @@ func (g *Game) SwappablesOtherThan(exclude string) map[string]player.Swappable {
 	}
 
 	for i, table := range g.tableCards {
-		name := fmt.Sprintf("#%d", i)
-		m[name] = table
+		m[tableCardName(i)] = table
 	}
 
 	return m
@@ -387,7 +467,7 @@ func (g *Game) CoinOwnersNextTo(nextto string) (before, after player.CoinOwner,
 	}
 
 	if !found {
-		return nil, nil, fmt.Errorf("No such player %s", nextto)
+		return nil, nil, noSuchPlayer(nextto)
 	}
 
 	beforeIndex := index - 1
@@ -431,13 +511,69 @@ func (g *Game) TakeCourthouse() (courthouse uint64) {
 }
 
 func (g *Game) CheaterWins(p *player.Player) {
-	g.cheatWinner = p
+	g.cheatWinners = append(g.cheatWinners, p)
 }
 
 func (g *Game) RevealCard(p *player.Player) {
 	p.Reveal(g.turnCount)
 }
 
+// Rules gives the rules the game is played by.
+func (g *Game) Rules() Rules {
+	return g.rules
+}
+
 func (g *Game) Winners() []string {
 	return g.winners
 }
+
+func (g *Game) WinCondition() WinCondition {
+	return g.winCondition
+}
+
+// Seating gives the players' names, in the order they sit.
+func (g *Game) Seating() []string {
+	names := make([]string, len(g.playerOrder))
+	for i, p := range g.playerOrder {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// Coins tells how many coins the named player has.
+func (g *Game) Coins(name string) (uint64, error) {
+	p, ok := g.players[name]
+	if !ok {
+		return 0, noSuchPlayer(name)
+	}
+	return p.Coins(), nil
+}
+
+// Courthouse tells how many coins fines have put in the courthouse.
+func (g *Game) Courthouse() uint64 {
+	return g.courthouse
+}
+
+// TurnCount tells how many turns have been played.
+func (g *Game) TurnCount() uint {
+	return g.turnCount
+}
+
+// Deck tells how many cards of each role are in the game.
+func (g *Game) Deck() map[role.Role]int {
+	deck := make(map[role.Role]int, len(g.roles))
+	for r, copies := range g.roles {
+		deck[r] = copies
+	}
+	return deck
+}
+
+// History gives everything the whole table has been told, oldest first, as
+// the text Formatter would put it. It is empty if the game was made without
+// its history.
+func (g *Game) History() []string {
+	if g.history == nil {
+		return nil
+	}
+	return g.history.Lines()
+}
@@ -2,11 +2,14 @@ package game
 
 import (
 	"bufio"
-	"fmt"
 	"io"
 	"math/rand"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/petertseng/mascarade/format"
 	"github.com/petertseng/mascarade/output"
@@ -15,54 +18,183 @@ import (
 )
 
 type GameBuilder struct {
-	roles       map[role.Role]bool
-	playerNames []string
+	// How many cards of each role are in the game.
+	roles        map[role.Role]int
+	playerNames  []string
+	choiceGetter ChoiceGetter
+	rng          *rand.Rand
+
+	rules       Rules
+	chooseRoles roleChooser
+	noHistory   bool
 }
 
 func NewBuilder() GameBuilder {
-	roles := make(map[role.Role]bool)
+	roles := make(map[role.Role]int)
 	playerNames := make([]string, 0)
 
-	return GameBuilder{roles: roles, playerNames: playerNames}
+	return GameBuilder{roles: roles, playerNames: playerNames, rules: OfficialRules()}
+}
+
+// How many players the rules allow.
+const (
+	MinPlayers = 2
+	MaxPlayers = 13
+)
+
+// Games with fewer players than this have table cards to make up as many cards.
+const minCards = 6
+
+// NumCards gives the fewest cards the rules say to deal for the given number
+// of players. Whatever the players don't get goes on the table, so any more
+// roles become table cards too.
+func NumCards(players int) int {
+	if players < minCards {
+		return minCards
+	}
+	return players
+}
+
+// CheckPlayerName tells why name can't be a player's name, if it can't:
+// it must not be empty, hold spaces, nor look like a table card's name.
+func CheckPlayerName(name string) error {
+	params := map[string]string{"name": name}
+	if name == "" {
+		return newRuleError(CodeBadPlayerName, params, "A player's name can't be empty")
+	}
+	if strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+		return newRuleError(CodeBadPlayerName, params, "%q can't be a player's name, because it has spaces", name)
+	}
+	if _, ok := parseTableCard(name); ok {
+		return newRuleError(CodeBadPlayerName, params, "%s can't be a player's name, because it would be taken for a table card", name)
+	}
+	return nil
 }
 
+// AddPlayer seats a player, whose name must differ from the others' even
+// ignoring case, so that names typed as targets can't be mistaken.
 func (gb *GameBuilder) AddPlayer(name string) error {
-	// TODO: What if player is already in the game
+	if err := CheckPlayerName(name); err != nil {
+		return err
+	}
+	for _, existing := range gb.playerNames {
+		if strings.EqualFold(existing, name) {
+			return newRuleError(CodeDuplicatePlayer, map[string]string{"name": name}, "%s is already in the game", existing)
+		}
+	}
 	gb.playerNames = append(gb.playerNames, name)
 	return nil
 }
 
+// AddRole puts a role in the game, with as many copies as it has unless told
+// otherwise: two Peasants, or one of anything else. Adding it again changes nothing.
 func (gb *GameBuilder) AddRole(name string) error {
-	role, err := role.FromString(name)
+	r, err := role.FromString(name)
 	if err != nil {
-		return err
+		return noSuchRole(name, err)
+	}
+	if gb.roles[r] == 0 {
+		gb.roles[r] = r.Copies()
+	}
+	return nil
+}
+
+// AddRoleCopies puts copies cards of a role in the game, such as two Queens or
+// three Peasants, in place of however many it had. No copies takes it out.
+func (gb *GameBuilder) AddRoleCopies(name string, copies int) error {
+	r, err := role.FromString(name)
+	if err != nil {
+		return noSuchRole(name, err)
+	}
+	if copies < 0 {
+		return newRuleError(CodeBadCopies, map[string]string{"role": r.String(), "copies": strconv.Itoa(copies)}, "Can't have %d copies of the %s", copies, r)
+	}
+	if copies == 0 {
+		delete(gb.roles, r)
+	} else {
+		gb.roles[r] = copies
 	}
-	gb.roles[role] = true
 	return nil
 }
 
+// SetChoiceGetter makes the game ask choiceGetter for players' answers to
+// power prompts, instead of reading them from standard input.
+func (gb *GameBuilder) SetChoiceGetter(choiceGetter ChoiceGetter) {
+	gb.choiceGetter = choiceGetter
+}
+
+// SetSeed makes the seating and the deal depend only on seed, so that a game can be repeated.
+func (gb *GameBuilder) SetSeed(seed int64) {
+	gb.rng = rand.New(rand.NewSource(seed))
+}
+
+// SetOpeningReveal says whether every card is shown to everyone before the
+// first turn, as the official rules say. Without it, the game starts with
+// nobody knowing anything.
+func (gb *GameBuilder) SetOpeningReveal(reveal bool) {
+	gb.rules.OpeningReveal = reveal
+}
+
+// SetHistory says whether the game keeps its History, as it does unless told
+// otherwise. Games nobody will look back on, such as those bots play among
+// themselves, are quicker without.
+func (gb *GameBuilder) SetHistory(keep bool) {
+	gb.noHistory = !keep
+}
+
 func (gb *GameBuilder) MakeGame(out io.Writer) (Game, error) {
+	return gb.MakeGameWithFormatter(format.NewText(output.NewPrefixed(out)))
+}
+
+// MakeGameWithFormatter makes a game that tells f of everything that happens.
+func (gb *GameBuilder) MakeGameWithFormatter(f format.Formatter) (Game, error) {
+	players := len(gb.playerNames)
+	if players < MinPlayers || players > MaxPlayers {
+		params := map[string]string{"players": strconv.Itoa(players)}
+		return Game{}, newRuleError(CodePlayerCount, params, "A game needs %d to %d players, not %d.", MinPlayers, MaxPlayers, players)
+	}
+
+	rng := gb.rng
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
+	}
+
+	deck := make(map[role.Role]int, len(gb.roles))
+	for r, copies := range gb.roles {
+		deck[r] = copies
+	}
+	if gb.chooseRoles != nil {
+		if len(gb.roles) > 0 {
+			return Game{}, newRuleError(CodeRolesTwice, nil, "Roles can't be both added and chosen by a preset")
+		}
+		chosen, err := gb.chooseRoles(players, rng)
+		if err != nil {
+			return Game{}, err
+		}
+		for _, r := range chosen {
+			deck[r] = r.Copies()
+		}
+	}
+
 	// Make the roles array
 	roles := make([]role.Role, 0)
-	rolesPresent := make(map[role.Role]bool)
-	for role, included := range gb.roles {
-		if included {
+	for role, copies := range deck {
+		for i := 0; i < copies; i++ {
 			roles = append(roles, role)
-			rolesPresent[role] = true
-			if role.Pair() {
-				roles = append(roles, role)
-			}
 		}
 	}
 
-	if len(roles) < len(gb.playerNames) {
-		err := fmt.Errorf("Not enough roles (%d) for the players (%d).", len(roles), len(gb.playerNames))
-		return Game{}, err
+	cards := NumCards(players)
+	params := map[string]string{"roles": strconv.Itoa(len(roles)), "players": strconv.Itoa(players), "cards": strconv.Itoa(cards)}
+	if len(roles) < cards {
+		return Game{}, newRuleError(CodeNotEnoughRoles, params, "Not enough roles (%d) for the players (%d); the rules call for %d cards.", len(roles), players, cards)
 	}
 
-	rand.Seed(time.Now().UTC().UnixNano())
-	playerPerm := rand.Perm(len(gb.playerNames))
-	rolePerm := rand.Perm(len(roles))
+	// Map order is random, so put the roles in order before dealing them.
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+
+	playerPerm := rng.Perm(len(gb.playerNames))
+	rolePerm := rng.Perm(len(roles))
 
 	playerOrder := make([]*player.Player, len(gb.playerNames))
 	playerMap := make(map[string]*player.Player)
@@ -70,7 +202,7 @@ func (gb *GameBuilder) MakeGame(out io.Writer) (Game, error) {
 	for i, name := range gb.playerNames {
 		seatingOrder := playerPerm[i]
 		role := roles[rolePerm[i]]
-		p := player.New(name, role)
+		p := player.New(name, role, gb.rules.StartingCoins)
 		playerOrder[seatingOrder] = &p
 		playerMap[name] = &p
 	}
@@ -83,13 +215,21 @@ func (gb *GameBuilder) MakeGame(out io.Writer) (Game, error) {
 		tableCards[i] = &tc
 	}
 
+	var history *output.Log
+	if !gb.noHistory {
+		history = output.NewLog()
+		f = format.NewTee(format.NewText(history), f)
+	}
 	g := Game{
-		roles:       rolesPresent,
-		players:     playerMap,
-		playerOrder: playerOrder,
-		tableCards:  tableCards,
-		input:       bufio.NewReader(os.Stdin),
-		format:      format.NewText(output.NewPrefixed(out)),
+		roles:        deck,
+		players:      playerMap,
+		playerOrder:  playerOrder,
+		tableCards:   tableCards,
+		input:        bufio.NewReader(os.Stdin),
+		choiceGetter: gb.choiceGetter,
+		format:       f,
+		history:      history,
+		rules:        gb.rules,
 	}
 	g.startGame()
 	return g, nil
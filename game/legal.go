@@ -0,0 +1,68 @@
+package game
+
+// MustSwap tells whether the rules force the player whose view it is to swap
+// (or not) on their turn, either because it's one of the first few turns or
+// because they revealed their card on the previous turn.
+func (v View) MustSwap() bool {
+	if v.TurnCount < v.Rules.SwapTurns {
+		return true
+	}
+	self, _ := v.Player(v.Self)
+	return v.TurnCount > 0 && self.Revealed && self.LastRevealed == v.TurnCount-1
+}
+
+// SwapTargets lists everything the player whose view it is may swap with:
+// everyone else, then the table cards, named as SwapOrNot accepts them.
+func (v View) SwapTargets() []string {
+	targets := make([]string, 0, len(v.Players)+len(v.TableCards))
+	for _, p := range v.Players {
+		if p.Name != v.Self {
+			targets = append(targets, p.Name)
+		}
+	}
+	return append(targets, v.TableCards...)
+}
+
+// LegalActions lists every move the game would accept from the player whose
+// view it is, or nothing if somebody else must act.
+func (v View) LegalActions() []Action {
+	if v.Self != v.Active {
+		return nil
+	}
+
+	if v.Claimant != "" {
+		return []Action{
+			{Kind: ChallengeAction},
+			{Kind: NoChallengeAction},
+		}
+	}
+
+	actions := make([]Action, 0)
+	for _, target := range v.SwapTargets() {
+		actions = append(actions,
+			Action{Kind: SwapAction, Target: target, ActuallySwap: true},
+			Action{Kind: SwapAction, Target: target, ActuallySwap: false},
+		)
+	}
+
+	if v.MustSwap() {
+		return actions
+	}
+
+	actions = append(actions, Action{Kind: PeekAction})
+	for _, r := range v.Roles {
+		if r.CanAnnounce() {
+			actions = append(actions, Action{Kind: ClaimAction, Role: r})
+		}
+	}
+	return actions
+}
+
+// LegalActions lists every move the game would accept from whoever must act now,
+// or nothing once the game is over.
+func (g *Game) LegalActions() []Action {
+	if len(g.Winners()) > 0 {
+		return nil
+	}
+	return g.View(g.ActivePlayerName()).LegalActions()
+}
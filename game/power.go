@@ -2,6 +2,7 @@ package game
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -12,105 +13,125 @@ import (
 
 type Power func(game GameResolver, user *player.Player, numCorrect int, format format.Formatter)
 
-func waitForSwappables(choiceGetter func(string) []string, format format.Formatter, user string, possibleChoices map[string]player.Swappable, num int) []player.Swappable {
+func waitForSwappables(choiceGetter ChoiceGetter, format format.Formatter, user string, r role.Role, possibleChoices map[string]player.Swappable, num int) []player.Swappable {
+	prompt := Prompt{Player: user, Kind: ChooseSwappables, Power: r, Num: num, Choices: sortedNames(possibleChoices)}
 	for {
-		names := choiceGetter(user)
+		names := SplitTargets(strings.Join(choiceGetter(prompt), " "))
 		choices := make([]player.Swappable, 0)
 		seen := make(map[string]bool)
 		for _, name := range names {
-			if _, ok := seen[name]; ok {
-				format.Error(user, fmt.Errorf("You must select %d different cards but you selected %s twice", num, name))
+			target, err := resolveTarget(name, prompt.Choices, "")
+			if err != nil {
+				format.Error(user, err)
 				continue
 			}
-
-			choice, ok := possibleChoices[name]
-			if ok {
-				choices = append(choices, choice)
-				seen[name] = true
-			} else {
-				format.Error(user, fmt.Errorf("No such player %s", name))
+			if _, ok := seen[target]; ok {
+				format.Error(user, newRuleError(CodeDuplicateChoice, map[string]string{"name": target, "num": strconv.Itoa(num)}, "You must select %d different cards but you selected %s twice", num, target))
+				continue
 			}
+
+			choices = append(choices, possibleChoices[target])
+			seen[target] = true
 		}
 		if len(choices) != num {
-			format.Error(user, fmt.Errorf("You must select %d players", num))
+			format.Error(user, newRuleError(CodeWrongNumberOfChoices, map[string]string{"num": strconv.Itoa(num)}, "You must select %d players", num))
 			continue
 		}
 		return choices
 	}
 }
 
-func waitForPlayers(choiceGetter func(string) []string, format format.Formatter, user string, possibleChoices map[string]*player.Player, num int) []*player.Player {
+func waitForPlayers(choiceGetter ChoiceGetter, format format.Formatter, user string, r role.Role, possibleChoices map[string]*player.Player, num int) []*player.Player {
+	prompt := Prompt{Player: user, Kind: ChoosePlayers, Power: r, Num: num, Choices: sortedNames(possibleChoices)}
 	for {
-		names := choiceGetter(user)
+		names := SplitTargets(strings.Join(choiceGetter(prompt), " "))
 		choices := make([]*player.Player, 0)
 		seen := make(map[string]bool)
 		for _, name := range names {
-			if _, ok := seen[name]; ok {
-				format.Error(user, fmt.Errorf("You must select %d different players but you selected %s twice", num, name))
+			target, err := resolveTarget(name, prompt.Choices, "")
+			if err != nil {
+				format.Error(user, err)
 				continue
 			}
-
-			choice, ok := possibleChoices[name]
-			if ok {
-				choices = append(choices, choice)
-				seen[name] = true
-			} else {
-				format.Error(user, fmt.Errorf("No such player %s", name))
+			if _, ok := seen[target]; ok {
+				format.Error(user, newRuleError(CodeDuplicateChoice, map[string]string{"name": target, "num": strconv.Itoa(num)}, "You must select %d different players but you selected %s twice", num, target))
+				continue
 			}
+
+			choices = append(choices, possibleChoices[target])
+			seen[target] = true
 		}
 		if len(choices) != num {
-			format.Error(user, fmt.Errorf("You must select %d players", num))
+			format.Error(user, newRuleError(CodeWrongNumberOfChoices, map[string]string{"num": strconv.Itoa(num)}, "You must select %d players", num))
 			continue
 		}
 		return choices
 	}
 }
 
-func waitForCoinOwner(choiceGetter func(string) []string, format format.Formatter, user string, possibleChoices map[string]player.CoinOwner) player.CoinOwner {
+func waitForCoinOwner(choiceGetter ChoiceGetter, format format.Formatter, user string, r role.Role, possibleChoices map[string]player.CoinOwner) player.CoinOwner {
+	prompt := Prompt{Player: user, Kind: ChooseCoinOwner, Power: r, Num: 1, Choices: sortedNames(possibleChoices)}
 	for {
-		names := choiceGetter(user)
+		names := choiceGetter(prompt)
 		if len(names) == 0 {
+			format.Error(user, newRuleError(CodeWrongNumberOfChoices, map[string]string{"num": "1"}, "You must select 1 player"))
 			continue
 		}
-		choice, ok := possibleChoices[names[0]]
-		if ok {
-			return choice
+		target, err := resolveTarget(strings.Join(names, " "), prompt.Choices, "")
+		if err == nil {
+			return possibleChoices[target]
 		} else {
-			format.Error(user, fmt.Errorf("No such player %s", names[0]))
+			format.Error(user, err)
 		}
 	}
 }
 
-func waitForRole(choiceGetter func(string) []string, format format.Formatter, user string) role.Role {
+func waitForRole(choiceGetter ChoiceGetter, format format.Formatter, user string, r role.Role) role.Role {
+	prompt := Prompt{Player: user, Kind: ChooseRole, Power: r, Num: 1}
 	for {
-		choices := choiceGetter(user)
+		choices := choiceGetter(prompt)
 		if len(choices) == 0 {
+			format.Error(user, newRuleError(CodeWrongNumberOfChoices, map[string]string{"num": "1"}, "You must name a role"))
 			continue
 		}
-		r, err := role.FromString(choices[0])
+		// Some roles' names are more than one word.
+		name := strings.Join(choices, " ")
+		r, err := role.FromString(name)
 		if err == nil {
 			return r
 		} else {
-			format.Error(user, err)
+			format.Error(user, noSuchRole(name, err))
 		}
 	}
 }
 
-func waitForBoolean(choiceGetter func(string) []string, format format.Formatter, user string) bool {
+func waitForBoolean(choiceGetter ChoiceGetter, format format.Formatter, user string, r role.Role) bool {
+	prompt := Prompt{Player: user, Kind: ChooseBoolean, Power: r, Num: 1}
 	for {
-		choices := choiceGetter(user)
+		choices := choiceGetter(prompt)
 		if len(choices) == 0 {
+			format.Error(user, newRuleError(CodeNotBoolean, map[string]string{"answer": ""}, "You must answer yes or no"))
 			continue
 		}
-		actual, err := strconv.ParseBool(choices[0])
+		actual, err := ParseBool(choices[0])
 		if err == nil {
 			return actual
 		} else {
-			format.Error(user, err)
+			format.Error(user, newRuleError(CodeNotBoolean, map[string]string{"answer": choices[0]}, "%s is neither yes nor no", choices[0]))
 		}
 	}
 }
 
+// PlayableRoles lists, in order, the roles whose powers are implemented.
+func PlayableRoles() []role.Role {
+	roles := make([]role.Role, 0, len(powers))
+	for r := range powers {
+		roles = append(roles, r)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+	return roles
+}
+
 var powers map[role.Role]Power = map[role.Role]Power{
 	role.Judge: func(game GameResolver, user *player.Player, _ int, format format.Formatter) {
 		coins := game.TakeCourthouse()
@@ -132,7 +153,7 @@ var powers map[role.Role]Power = map[role.Role]Power{
 				names = append(names, name)
 			}
 			format.PromptForPlayer(user.Name(), role.Bishop, 1, fmt.Sprintf("The richest players are: %s.", strings.Join(names, ", ")))
-			coinOwner = waitForCoinOwner(game.UserChoice, format, user.Name(), richest)
+			coinOwner = waitForCoinOwner(game.UserChoice, format, user.Name(), role.Bishop, richest)
 		}
 
 		coinOwner.Pay(user, 2)
@@ -150,12 +171,13 @@ var powers map[role.Role]Power = map[role.Role]Power{
 
 		swappables := game.SwappablesOtherThan(user.Name())
 		format.PromptForSwappable(user.Name(), role.Fool, 2)
-		choices := waitForSwappables(game.UserChoice, format, user.Name(), swappables, 2)
+		choices := waitForSwappables(game.UserChoice, format, user.Name(), role.Fool, swappables, 2)
 		format.PromptForSwap(user.Name())
-		actualSwap := waitForBoolean(game.UserChoice, format, user.Name())
+		actualSwap := waitForBoolean(game.UserChoice, format, user.Name(), role.Fool)
 		if actualSwap {
 			choices[0].SwapRoles(choices[1])
 		}
+		format.SwapOrNotOthers(user.Name(), choices[0].Name(), choices[1].Name())
 	},
 
 	role.Queen: func(game GameResolver, user *player.Player, _ int, format format.Formatter) {
@@ -177,7 +199,7 @@ var powers map[role.Role]Power = map[role.Role]Power{
 	role.Witch: func(game GameResolver, user *player.Player, _ int, format format.Formatter) {
 		coinOwners := game.CoinOwners()
 		format.PromptForPlayer(user.Name(), role.Witch, 1, "Choose yourself to not swap.")
-		coinOwner := waitForCoinOwner(game.UserChoice, format, user.Name(), coinOwners)
+		coinOwner := waitForCoinOwner(game.UserChoice, format, user.Name(), role.Witch, coinOwners)
 		var coinsToGive uint64
 		var giver, receiver player.CoinOwner
 
@@ -199,7 +221,8 @@ var powers map[role.Role]Power = map[role.Role]Power{
 	},
 
 	role.Peasant: func(game GameResolver, user *player.Player, numCorrect int, format format.Formatter) {
-		if numCorrect == 2 {
+		// With more than one Peasant revealed, each takes 2.
+		if numCorrect > 1 {
 			user.AddCoins(2)
 			format.GainCoins(user.Name(), 2, user.Coins())
 		} else {
@@ -212,17 +235,18 @@ var powers map[role.Role]Power = map[role.Role]Power{
 		format.TellOwnCard(user.Name(), user.Role())
 		swappables := game.SwappablesOtherThan(user.Name())
 		format.PromptForSwappable(user.Name(), role.Spy, 1)
-		choice := waitForSwappables(game.UserChoice, format, user.Name(), swappables, 1)[0]
+		choice := waitForSwappables(game.UserChoice, format, user.Name(), role.Spy, swappables, 1)[0]
 		format.TellCard(user.Name(), choice.Name(), choice.Role())
 		format.PromptForSwap(user.Name())
-		actualSwap := waitForBoolean(game.UserChoice, format, user.Name())
+		actualSwap := waitForBoolean(game.UserChoice, format, user.Name(), role.Spy)
 		if actualSwap {
 			user.SwapRoles(choice)
 		}
+		format.SwapOrNot(user.Name(), choice.Name())
 	},
 
 	role.Cheat: func(game GameResolver, user *player.Player, _ int, format format.Formatter) {
-		if user.Coins() >= 10 {
+		if user.Coins() >= game.Rules().CheatCoins {
 			game.CheaterWins(user)
 		}
 	},
@@ -230,9 +254,9 @@ var powers map[role.Role]Power = map[role.Role]Power{
 	role.Inquisitor: func(game GameResolver, user *player.Player, _ int, format format.Formatter) {
 		players := game.PlayersOtherThan(user.Name())
 		format.PromptForPlayer(user.Name(), role.Inquisitor, 1, "")
-		choice := waitForPlayers(game.UserChoice, format, user.Name(), players, 1)[0]
+		choice := waitForPlayers(game.UserChoice, format, user.Name(), role.Inquisitor, players, 1)[0]
 		format.PromptForRole(choice.Name())
-		guess := waitForRole(game.UserChoice, format, choice.Name())
+		guess := waitForRole(game.UserChoice, format, choice.Name(), role.Inquisitor)
 
 		game.RevealCard(choice)
 
@@ -246,10 +270,10 @@ var powers map[role.Role]Power = map[role.Role]Power{
 	},
 
 	role.Widow: func(game GameResolver, user *player.Player, _ int, format format.Formatter) {
-		if user.Coins() < 10 {
-			coinsToGive := 10 - user.Coins()
-			user.AddCoins(10 - user.Coins())
-			format.GainCoins(user.Name(), coinsToGive, 10)
+		if target := game.Rules().WidowCoins; user.Coins() < target {
+			coinsToGive := target - user.Coins()
+			user.AddCoins(coinsToGive)
+			format.GainCoins(user.Name(), coinsToGive, target)
 		}
 	},
 }
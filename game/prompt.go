@@ -0,0 +1,89 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/petertseng/mascarade/role"
+)
+
+// PromptKind says what sort of answer a Prompt is waiting for.
+type PromptKind int
+
+const (
+	// Num different names from Choices.
+	ChooseSwappables PromptKind = iota
+	// Num different names from Choices.
+	ChoosePlayers
+	// One name from Choices.
+	ChooseCoinOwner
+	// The name of any role.
+	ChooseRole
+	// Whether to actually swap, as understood by ParseBool.
+	ChooseBoolean
+)
+
+var promptKindNames = map[PromptKind]string{
+	ChooseSwappables: "swappables",
+	ChoosePlayers:    "players",
+	ChooseCoinOwner:  "coin_owner",
+	ChooseRole:       "role",
+	ChooseBoolean:    "boolean",
+}
+
+func (k PromptKind) String() string {
+	if name, ok := promptKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("Unknown prompt kind %d", int(k))
+}
+
+func (k PromptKind) MarshalText() ([]byte, error) {
+	if _, ok := promptKindNames[k]; !ok {
+		return nil, fmt.Errorf("Unknown prompt kind %d", int(k))
+	}
+	return []byte(k.String()), nil
+}
+
+func (k *PromptKind) UnmarshalText(text []byte) error {
+	for kind, name := range promptKindNames {
+		if name == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("No such prompt kind %s", text)
+}
+
+var bools = map[string]bool{
+	"yes": true, "y": true, "true": true, "t": true, "1": true,
+	"no": false, "n": false, "false": false, "f": false, "0": false,
+}
+
+// ParseBool reads a yes or no: yes, y, true, t or 1, or no, n, false, f or 0, in any case.
+func ParseBool(word string) (bool, error) {
+	b, ok := bools[strings.ToLower(word)]
+	if !ok {
+		return false, fmt.Errorf("%s is neither yes nor no", word)
+	}
+	return b, nil
+}
+
+// A Prompt is a question asked of a player while a power is being used.
+type Prompt struct {
+	Player  string     `json:"player"`
+	Kind    PromptKind `json:"kind"`
+	Power   role.Role  `json:"power"`
+	Num     int        `json:"num"`
+	Choices []string   `json:"choices,omitempty"`
+}
+
+func sortedNames[T any](m map[string]T) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
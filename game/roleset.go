@@ -0,0 +1,136 @@
+package game
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+
+	"github.com/petertseng/mascarade/role"
+)
+
+// A roleChooser picks the roles for a game of some number of players.
+// A role in the game twice, such as the Peasant, is listed once.
+type roleChooser func(players int, rng *rand.Rand) ([]role.Role, error)
+
+// Roles are added to the starter sets in this order as there are more
+// players, skipping the Peasants when there is room for only one of them.
+// The order is ours, not the rulebook's.
+var starterOrder = []role.Role{
+	role.Judge, role.Bishop, role.King, role.Fool, role.Queen, role.Thief,
+	role.Witch, role.Spy, role.Cheat, role.Inquisitor, role.Peasant, role.Widow,
+}
+
+// CoreRoles are in every random set made by the "random" preset.
+var CoreRoles = []role.Role{role.Judge, role.King}
+
+// The rulebook's sets for each number of players aren't among the presets,
+// since we have yet to copy them out, and neither are mixes with the
+// expansion, since none of its powers are implemented yet.
+var presets = map[string]roleChooser{
+	// The simplest powers for small games, with more as there are more players.
+	"starter": func(players int, _ *rand.Rand) ([]role.Role, error) {
+		cards := NumCards(players)
+		chosen, count := fill(nil, starterOrder, cards)
+		if count != cards {
+			return nil, cantChooseRoles(cards)
+		}
+		return chosen, nil
+	},
+	// A random set of the roles that can be played, always with the CoreRoles.
+	"random": randomRoles(CoreRoles, PlayableRoles),
+	// As "random", but only with roles from the base game.
+	"base": randomRoles(CoreRoles, func() []role.Role {
+		base := make([]role.Role, 0)
+		for _, r := range PlayableRoles() {
+			if r.Base() {
+				base = append(base, r)
+			}
+		}
+		return base
+	}),
+}
+
+// Presets lists the names UsePreset knows of, in order.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UsePreset chooses the roles when the game is made, according to the preset
+// of that name and the number of players. No roles may be added by hand.
+func (gb *GameBuilder) UsePreset(name string) error {
+	chooser, ok := presets[name]
+	if !ok {
+		return newRuleError(CodeNoSuchPreset, map[string]string{"name": name}, "No such preset %s; choose from %v", name, Presets())
+	}
+	gb.chooseRoles = chooser
+	return nil
+}
+
+// UseRandomRoles chooses a random set of the roles that can be played when
+// the game is made, always including required. The Peasants are both in or both out.
+// No roles may be added by hand.
+func (gb *GameBuilder) UseRandomRoles(required []role.Role) {
+	gb.chooseRoles = randomRoles(append([]role.Role(nil), required...), PlayableRoles)
+}
+
+// randomRoles chooses required and then others from candidates at random.
+func randomRoles(required []role.Role, candidates func() []role.Role) roleChooser {
+	return func(players int, rng *rand.Rand) ([]role.Role, error) {
+		cards := NumCards(players)
+		chosen, count := fill(nil, required, cards)
+		if len(chosen) < len(distinct(required)) {
+			return nil, cantChooseRoles(cards)
+		}
+
+		roles := candidates()
+		shuffled := make([]role.Role, len(roles))
+		for i, j := range rng.Perm(len(roles)) {
+			shuffled[i] = roles[j]
+		}
+		if chosen, count = fill(chosen, shuffled, cards); count != cards {
+			return nil, cantChooseRoles(cards)
+		}
+		return chosen, nil
+	}
+}
+
+// fill adds roles from candidates to chosen in order, skipping those already
+// chosen and those there is no room for, until there are enough cards.
+// It gives how many cards there are by then.
+func fill(chosen, candidates []role.Role, cards int) ([]role.Role, int) {
+	count := 0
+	in := make(map[role.Role]bool)
+	for _, r := range chosen {
+		count += r.Copies()
+		in[r] = true
+	}
+
+	for _, r := range candidates {
+		if count == cards {
+			break
+		}
+		if !in[r] && count+r.Copies() <= cards {
+			chosen = append(chosen, r)
+			count += r.Copies()
+			in[r] = true
+		}
+	}
+	return chosen, count
+}
+
+func distinct(roles []role.Role) map[role.Role]bool {
+	set := make(map[role.Role]bool)
+	for _, r := range roles {
+		set[r] = true
+	}
+	return set
+}
+
+func cantChooseRoles(cards int) error {
+	return newRuleError(CodeCantChooseRoles, map[string]string{"cards": strconv.Itoa(cards)}, "Can't choose roles for exactly %d cards", cards)
+}
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/petertseng/mascarade/role"
+)
+
+// Rules holds the numbers a game is played with, so that tables can play
+// house rules or try out how the game balances with others.
+type Rules struct {
+	StartingCoins uint64 `json:"starting_coins"`
+	// Whoever has this many coins wins.
+	WinningCoins uint64 `json:"winning_coins"`
+	// Whether the game ends as soon as somebody has no coins, won by the richest.
+	BrokeEndsGame bool `json:"broke_ends_game"`
+	// The Cheat wins with this many coins.
+	CheatCoins uint64 `json:"cheat_coins"`
+	// The Widow takes coins until she has this many.
+	WidowCoins uint64 `json:"widow_coins"`
+	// For this many turns at the start, players may only swap (or not).
+	SwapTurns uint `json:"swap_turns"`
+	// What a false claim costs, paid to the courthouse.
+	Fine uint64 `json:"fine"`
+	// Whether every card is shown to everyone before the first turn.
+	OpeningReveal bool `json:"opening_reveal"`
+}
+
+// OfficialRules are the rules as printed.
+func OfficialRules() Rules {
+	return Rules{
+		StartingCoins: 6,
+		WinningCoins:  13,
+		BrokeEndsGame: true,
+		CheatCoins:    10,
+		WidowCoins:    10,
+		SwapTurns:     4,
+		Fine:          1,
+		OpeningReveal: true,
+	}
+}
+
+// PowerDescription describes what r does when played by these rules.
+func (r Rules) PowerDescription(ro role.Role) string {
+	switch ro {
+	case role.Cheat:
+		return fmt.Sprintf("Wins with %d coins", r.CheatCoins)
+	case role.Widow:
+		return fmt.Sprintf("Take coins from the bank until at %d coins", r.WidowCoins)
+	}
+	return ro.PowerDescription()
+}
+
+// More turns than this at the start when players may only swap (or not), and
+// no game anyone plays would ever get to a claim.
+const maxSwapTurns = 100
+
+// Check tells why a game can't be played by r, if it can't: a RulesError with
+// every rule that is wrong.
+func (r Rules) Check() error {
+	var errs RulesError
+	if r.StartingCoins == 0 {
+		errs = append(errs, badRule("starting_coins", "Players must start with some coins"))
+	}
+	if r.WinningCoins <= r.StartingCoins {
+		errs = append(errs, badRule("winning_coins", "Players must need more than the %d coins they start with to win, not %d", r.StartingCoins, r.WinningCoins))
+	}
+	if r.CheatCoins == 0 {
+		errs = append(errs, badRule("cheat_coins", "The Cheat must need some coins to win"))
+	} else if r.CheatCoins >= r.WinningCoins {
+		errs = append(errs, badRule("cheat_coins", "The Cheat must need fewer coins to win than the %d anyone wins with, not %d", r.WinningCoins, r.CheatCoins))
+	}
+	if r.WidowCoins == 0 {
+		errs = append(errs, badRule("widow_coins", "The Widow must take coins up to some number"))
+	}
+	if r.SwapTurns > maxSwapTurns {
+		errs = append(errs, badRule("swap_turns", "Players can't be made to swap (or not) for more than %d turns, not %d, or nobody would ever claim", maxSwapTurns, r.SwapTurns))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func badRule(field string, format string, a ...interface{}) *RuleError {
+	return newRuleError(CodeBadRule, map[string]string{"field": field}, format, a...)
+}
+
+// A RulesError is every problem Check found with some Rules, in the order of
+// the fields. Each has the code bad_rule, and names its rule's JSON field in
+// the param field.
+type RulesError []*RuleError
+
+func (e RulesError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap lets errors.Is and errors.As look at every problem.
+func (e RulesError) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// SetRules makes the game be played by r in place of the official rules.
+func (gb *GameBuilder) SetRules(r Rules) error {
+	if err := r.Check(); err != nil {
+		return err
+	}
+	gb.rules = r
+	return nil
+}
+
+// mustSwap tells why the active player may only swap (or not), if they may.
+func (g *Game) mustSwap() error {
+	if g.turnCount < g.rules.SwapTurns {
+		turns := strconv.FormatUint(uint64(g.rules.SwapTurns), 10)
+		return newRuleError(CodeMustSwapEarly, map[string]string{"turns": turns}, "For the first %s turns, you must swap (or not)", turns)
+	}
+	if g.turnCount > 0 && g.activePlayer().RevealedOn(g.turnCount-1) {
+		return newRuleError(CodeMustSwapRevealed, map[string]string{"player": g.ActivePlayerName()}, "Because you revealed your card on the previous turn, you must swap (or not)")
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRulesCheck(t *testing.T) {
+	tests := []struct {
+		desc   string
+		change func(r *Rules)
+		fields []string
+	}{
+		{"official", func(r *Rules) {}, nil},
+		{"no starting coins", func(r *Rules) { r.StartingCoins = 0 }, []string{"starting_coins"}},
+		{"winning with what players start with", func(r *Rules) { r.WinningCoins = r.StartingCoins }, []string{"winning_coins", "cheat_coins"}},
+		{"no cheat coins", func(r *Rules) { r.CheatCoins = 0 }, []string{"cheat_coins"}},
+		{"cheat needs as many as anyone", func(r *Rules) { r.CheatCoins = r.WinningCoins }, []string{"cheat_coins"}},
+		{"no widow coins", func(r *Rules) { r.WidowCoins = 0 }, []string{"widow_coins"}},
+		{"as many swap turns as may be", func(r *Rules) { r.SwapTurns = maxSwapTurns }, nil},
+		{"too many swap turns", func(r *Rules) { r.SwapTurns = maxSwapTurns + 1 }, []string{"swap_turns"}},
+		{"everything", func(r *Rules) { *r = Rules{SwapTurns: maxSwapTurns + 1} }, []string{"starting_coins", "winning_coins", "cheat_coins", "widow_coins", "swap_turns"}},
+	}
+
+	for _, test := range tests {
+		r := OfficialRules()
+		test.change(&r)
+		err := r.Check()
+		if test.fields == nil {
+			if err != nil {
+				t.Errorf("%s: %s", test.desc, err)
+			}
+			continue
+		}
+
+		var rulesErr RulesError
+		if !errors.As(err, &rulesErr) {
+			t.Errorf("%s: got %v, want a RulesError", test.desc, err)
+			continue
+		}
+		fields := make([]string, len(rulesErr))
+		for i, ruleErr := range rulesErr {
+			fields[i] = ruleErr.Params["field"]
+		}
+		if !reflect.DeepEqual(fields, test.fields) {
+			t.Errorf("%s: bad rules %q, want %q", test.desc, fields, test.fields)
+		}
+		if !errors.Is(err, ErrBadRule) {
+			t.Errorf("%s: %v is not %v", test.desc, err, ErrBadRule)
+		}
+	}
+}
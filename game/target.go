@@ -0,0 +1,139 @@
+package game
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Players and table cards are chosen as targets by name. A table card is
+// #0, #1, #2... or Table Card 0, Table Card 1... A player may be named in any
+// case, or by the start of their name if no other player's starts the same way.
+
+const tableCardWords = "table card"
+
+// parseTableCard tells whether name refers to a table card, such as #1 or
+// Table Card 1, and if so which; the index is -1 if it is not a number.
+func parseTableCard(name string) (int, bool) {
+	var number string
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "#"):
+		number = name[1:]
+	case strings.HasPrefix(lower, tableCardWords):
+		number = strings.TrimSpace(name[len(tableCardWords):])
+	default:
+		return 0, false
+	}
+
+	// Only plain numbers: no signs, spaces, or other bases.
+	for _, c := range number {
+		if c < '0' || c > '9' {
+			return -1, true
+		}
+	}
+	index, err := strconv.Atoi(number)
+	if err != nil {
+		return -1, true
+	}
+	return index, true
+}
+
+// resolveTarget finds which of names is meant by name, which may be a table
+// card in either form, or a player's name in any case or cut short.
+// unchoosable, if not empty, is among names but may not be chosen, so a
+// name that could also mean it is taken to mean the other player.
+func resolveTarget(name string, names []string, unchoosable string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", newRuleError(CodeNoSuchPlayer, map[string]string{"name": name}, "No player or table card was named")
+	}
+
+	tableCards := 0
+	for _, n := range names {
+		if strings.HasPrefix(n, "#") {
+			tableCards++
+		}
+	}
+	if index, ok := parseTableCard(name); ok {
+		target := tableCardName(index)
+		for _, n := range names {
+			if index >= 0 && n == target {
+				return n, nil
+			}
+		}
+		return "", noSuchTableCard(name, tableCards)
+	}
+
+	var sameName, prefixed []string
+	lower := strings.ToLower(name)
+	for _, n := range names {
+		if n == name {
+			return n, nil
+		}
+		if strings.HasPrefix(n, "#") {
+			continue
+		}
+		if strings.ToLower(n) == lower {
+			sameName = append(sameName, n)
+		} else if strings.HasPrefix(strings.ToLower(n), lower) {
+			prefixed = append(prefixed, n)
+		}
+	}
+	for _, matches := range [][]string{sameName, prefixed} {
+		if len(matches) > 1 {
+			matches = without(matches, unchoosable)
+		}
+		if len(matches) == 1 {
+			return matches[0], nil
+		}
+		if len(matches) > 1 {
+			return "", newRuleError(CodeAmbiguousTarget, map[string]string{"name": name, "matches": strings.Join(matches, ",")}, "%s could be any of %s", name, strings.Join(matches, ", "))
+		}
+	}
+	return "", noSuchPlayer(name)
+}
+
+// without gives names, leaving out name.
+func without(names []string, name string) []string {
+	kept := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != name {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+func noSuchTableCard(name string, tableCards int) *RuleError {
+	params := map[string]string{"name": name, "tablecards": strconv.Itoa(tableCards)}
+	if tableCards == 0 {
+		return newRuleError(CodeNoSuchTableCard, params, "There are no table cards to choose, so %s is invalid", name)
+	}
+	return newRuleError(CodeNoSuchTableCard, params, "No such table card %s; they are #0 to #%d", name, tableCards-1)
+}
+
+// SplitTargets splits a line naming several targets into their names,
+// keeping each Table Card N together.
+func SplitTargets(line string) []string {
+	fields := strings.Fields(line)
+	targets := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		if i+2 < len(fields) && strings.EqualFold(fields[i]+" "+fields[i+1], tableCardWords) && isNumber(fields[i+2]) {
+			targets = append(targets, strings.Join(fields[i:i+3], " "))
+			i += 2
+			continue
+		}
+		targets = append(targets, fields[i])
+	}
+	return targets
+}
+
+func isNumber(s string) bool {
+	for _, c := range s {
+		if !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return s != ""
+}
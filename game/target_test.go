@@ -0,0 +1,77 @@
+package game
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestResolveTarget(t *testing.T) {
+	names := []string{"Alice", "Albert", "Bob", "bobby", "Carol", "#0", "#1"}
+	tests := []struct {
+		name        string
+		unchoosable string
+		want        string
+		err         error
+	}{
+		{name: "Alice", want: "Alice"},
+		{name: "  Bob ", want: "Bob"},
+		{name: "carol", want: "Carol"},
+		{name: "ca", want: "Carol"},
+		{name: "BOB", want: "Bob"},
+		{name: "bobby", want: "bobby"},
+		{name: "bobb", want: "bobby"},
+		{name: "al", err: ErrAmbiguousTarget},
+		{name: "al", unchoosable: "Alice", want: "Albert"},
+		{name: "#1", want: "#1"},
+		{name: "table card 0", want: "#0"},
+		{name: "Table Card 1", want: "#1"},
+		{name: "#2", err: ErrNoSuchTableCard},
+		{name: "#-1", err: ErrNoSuchTableCard},
+		{name: "#one", err: ErrNoSuchTableCard},
+		{name: "Dave", err: ErrNoSuchPlayer},
+		{name: "", err: ErrNoSuchPlayer},
+	}
+
+	for _, test := range tests {
+		got, err := resolveTarget(test.name, names, test.unchoosable)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("resolveTarget(%q, %q) = %q, %v; want error %v", test.name, test.unchoosable, got, err, test.err)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("resolveTarget(%q, %q) = %q, %v; want %q", test.name, test.unchoosable, got, err, test.want)
+		}
+	}
+}
+
+func TestResolveTargetNoTableCards(t *testing.T) {
+	_, err := resolveTarget("#0", []string{"Alice", "Bob"}, "")
+	var ruleErr *RuleError
+	if !errors.As(err, &ruleErr) || ruleErr.Code != CodeNoSuchTableCard || ruleErr.Params["tablecards"] != "0" {
+		t.Errorf("Got %v, want %s with no table cards", err, CodeNoSuchTableCard)
+	}
+}
+
+func TestSplitTargets(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", []string{}},
+		{"Alice Bob", []string{"Alice", "Bob"}},
+		{"  #0   #1 ", []string{"#0", "#1"}},
+		{"Alice table card 2", []string{"Alice", "table card 2"}},
+		{"Table Card 0 Table Card 1", []string{"Table Card 0", "Table Card 1"}},
+		{"table card", []string{"table", "card"}},
+		{"table card x", []string{"table", "card", "x"}},
+	}
+
+	for _, test := range tests {
+		if got := SplitTargets(test.line); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SplitTargets(%q) = %q, want %q", test.line, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/petertseng/mascarade/role"
+)
+
+// PlayerView is what everyone at the table can see of a player.
+type PlayerView struct {
+	Name  string `json:"name"`
+	Coins uint64 `json:"coins"`
+	// Whether the player's card has ever been revealed, and if so, on which turn it last was.
+	Revealed     bool `json:"revealed"`
+	LastRevealed uint `json:"last_revealed"`
+}
+
+// A View is everything a player can see of a game, apart from what they
+// have been privately told along the way.
+type View struct {
+	Self string `json:"self"`
+
+	// In seating order.
+	Players    []PlayerView `json:"players"`
+	TableCards []string     `json:"table_cards"`
+	// Every role in the game once, in order.
+	Roles []role.Role `json:"roles"`
+	// How many cards of each role are in the game.
+	Deck map[role.Role]int `json:"deck"`
+
+	Rules Rules `json:"rules"`
+
+	TurnCount  uint   `json:"turn_count"`
+	Courthouse uint64 `json:"courthouse"`
+
+	// The player who must act now, whether on their turn or to challenge.
+	Active string `json:"active"`
+
+	// Empty unless a claim is waiting to be challenged.
+	Claimant    string    `json:"claimant,omitempty"`
+	ClaimedRole role.Role `json:"claimed_role,omitempty"`
+	Challengers []string  `json:"challengers,omitempty"`
+}
+
+func (g *Game) View(self string) View {
+	v := View{
+		Self:       self,
+		Players:    make([]PlayerView, len(g.playerOrder)),
+		TableCards: make([]string, len(g.tableCards)),
+		Roles:      make([]role.Role, 0, len(g.roles)),
+		Deck:       make(map[role.Role]int, len(g.roles)),
+		Rules:      g.rules,
+		TurnCount:  g.turnCount,
+		Courthouse: g.courthouse,
+		Active:     g.ActivePlayerName(),
+	}
+
+	for i, p := range g.playerOrder {
+		v.Players[i] = PlayerView{Name: p.Name(), Coins: p.Coins(), Revealed: p.Revealed(), LastRevealed: p.LastRevealed()}
+	}
+	for i := range g.tableCards {
+		v.TableCards[i] = tableCardName(i)
+	}
+	for r, copies := range g.roles {
+		v.Roles = append(v.Roles, r)
+		v.Deck[r] = copies
+	}
+	sort.Slice(v.Roles, func(i, j int) bool { return v.Roles[i] < v.Roles[j] })
+
+	if g.claim {
+		v.Claimant = g.AnnouncingPlayerName()
+		v.ClaimedRole = g.claimedRole
+		v.Challengers = make([]string, len(g.otherClaimants))
+		for i, p := range g.otherClaimants {
+			v.Challengers[i] = p.Name()
+		}
+	}
+
+	return v
+}
+
+// Player finds a player by name.
+func (v View) Player(name string) (PlayerView, bool) {
+	for _, p := range v.Players {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return PlayerView{}, false
+}
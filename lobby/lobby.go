@@ -0,0 +1,319 @@
+// Package lobby hosts many games at once, letting players join and leave
+// before a game starts, and removing each game once it has been won.
+package lobby
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+// A Lobby holds many games at once, from the time they are created and
+// players are gathering, until one of them is won.
+// All methods of a Lobby are safe for concurrent use.
+type Lobby struct {
+	mu     sync.Mutex
+	tables map[int]*table
+	nextID int
+}
+
+// Info describes one of the games in a Lobby.
+type Info struct {
+	ID      int
+	Players []string
+	Roles   []string
+	Started bool
+}
+
+type table struct {
+	// Guarded by the Lobby's mu.
+	players []string
+	roles   []string
+	started bool
+
+	// Guards game. Held for the whole of an action, including any time
+	// spent waiting on a player to answer a power prompt.
+	gameMu sync.Mutex
+	game   *game.Game
+
+	// Guards prompted. Answers are delivered on answers without holding gameMu.
+	promptMu sync.Mutex
+	prompted map[string]bool
+	answers  chan []string
+	closed   chan struct{}
+}
+
+// errClosed is returned by an action that was waiting on a prompt when its game was removed.
+var errClosed = fmt.Errorf("The game was removed while waiting for an answer")
+
+func New() *Lobby {
+	return &Lobby{tables: make(map[int]*table)}
+}
+
+// Create makes a new game with the given roles, which players may then Join.
+func (l *Lobby) Create(roles []string) (int, error) {
+	for _, name := range roles {
+		if _, err := role.FromString(name); err != nil {
+			return 0, err
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nextID++
+	l.tables[l.nextID] = &table{
+		players:  make([]string, 0),
+		roles:    append([]string{}, roles...),
+		prompted: make(map[string]bool),
+		answers:  make(chan []string),
+		closed:   make(chan struct{}),
+	}
+	return l.nextID, nil
+}
+
+// List describes every game in the lobby, ordered by ID.
+func (l *Lobby) List() []Info {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	infos := make([]Info, 0, len(l.tables))
+	for id, t := range l.tables {
+		infos = append(infos, Info{
+			ID:      id,
+			Players: append([]string{}, t.players...),
+			Roles:   append([]string{}, t.roles...),
+			Started: t.started,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// tableLocked must be called with l.mu held.
+func (l *Lobby) tableLocked(id int) (*table, error) {
+	t, ok := l.tables[id]
+	if !ok {
+		return nil, fmt.Errorf("No such game %d", id)
+	}
+	return t, nil
+}
+
+func (l *Lobby) Join(id int, name string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t, err := l.tableLocked(id)
+	if err != nil {
+		return err
+	}
+	if t.started {
+		return fmt.Errorf("Game %d has already started", id)
+	}
+	if err := game.CheckPlayerName(name); err != nil {
+		return err
+	}
+	if len(t.players) >= game.MaxPlayers {
+		return fmt.Errorf("Game %d already has %d players", id, game.MaxPlayers)
+	}
+	for _, p := range t.players {
+		if strings.EqualFold(p, name) {
+			return fmt.Errorf("%s is already in game %d", p, id)
+		}
+	}
+
+	t.players = append(t.players, name)
+	return nil
+}
+
+func (l *Lobby) Leave(id int, name string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t, err := l.tableLocked(id)
+	if err != nil {
+		return err
+	}
+	if t.started {
+		return fmt.Errorf("Game %d has already started", id)
+	}
+	for i, p := range t.players {
+		if p == name {
+			t.players = append(t.players[:i], t.players[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not in game %d", name, id)
+}
+
+// Start deals the cards for a game, which will write its events to out.
+// No players may join or leave once a game has started.
+func (l *Lobby) Start(id int, out io.Writer) error {
+	l.mu.Lock()
+	t, err := l.tableLocked(id)
+	if err != nil {
+		l.mu.Unlock()
+		return err
+	}
+	if t.started {
+		l.mu.Unlock()
+		return fmt.Errorf("Game %d has already started", id)
+	}
+	t.started = true
+	players := append([]string{}, t.players...)
+	roles := append([]string{}, t.roles...)
+	l.mu.Unlock()
+
+	gameBuilder := game.NewBuilder()
+	for _, name := range players {
+		gameBuilder.AddPlayer(name)
+	}
+	for _, name := range roles {
+		gameBuilder.AddRole(name)
+	}
+	gameBuilder.SetChoiceGetter(t.waitForAnswer)
+
+	// Nobody can reach the game before it is stored, but the lock keeps
+	// the race detector informed of that.
+	t.gameMu.Lock()
+	g, err := gameBuilder.MakeGame(out)
+	if err == nil {
+		t.game = &g
+	}
+	t.gameMu.Unlock()
+
+	if err != nil {
+		l.mu.Lock()
+		t.started = false
+		l.mu.Unlock()
+	}
+	return err
+}
+
+// Do performs an action on a started game, such as calling SwapOrNotAs or
+// ClaimRoleAs for the player who asked. Actions on one game are performed one at a time.
+// If the action wins the game, the game is removed from the lobby.
+func (l *Lobby) Do(id int, action func(g *game.Game) error) error {
+	l.mu.Lock()
+	t, err := l.tableLocked(id)
+	if err == nil && !t.started {
+		err = fmt.Errorf("Game %d has not started", id)
+	}
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	t.gameMu.Lock()
+	defer t.gameMu.Unlock()
+
+	if t.isClosed() {
+		return fmt.Errorf("No such game %d", id)
+	}
+
+	err = action(t.game)
+	if t.isClosed() {
+		return errClosed
+	}
+
+	if len(t.game.Winners()) > 0 {
+		l.remove(id, t)
+	}
+	return err
+}
+
+// Answer gives a player's answer to the power prompt that a game is waiting on.
+func (l *Lobby) Answer(id int, name string, choice []string) error {
+	l.mu.Lock()
+	t, err := l.tableLocked(id)
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	t.promptMu.Lock()
+	waiting := t.prompted[name]
+	delete(t.prompted, name)
+	t.promptMu.Unlock()
+
+	if !waiting {
+		return fmt.Errorf("Game %d is not waiting for an answer from %s", id, name)
+	}
+
+	select {
+	case t.answers <- choice:
+		return nil
+	case <-t.closed:
+		return fmt.Errorf("No such game %d", id)
+	}
+}
+
+// Remove tears down a game, whether or not it has started or finished.
+// An action that is waiting on a prompt in that game returns an error.
+func (l *Lobby) Remove(id int) error {
+	l.mu.Lock()
+	t, err := l.tableLocked(id)
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	l.remove(id, t)
+	return nil
+}
+
+func (l *Lobby) remove(id int, t *table) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.tables[id] != t {
+		return
+	}
+	delete(l.tables, id)
+	close(t.closed)
+}
+
+func (t *table) isClosed() bool {
+	select {
+	case <-t.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitForAnswer waits for the prompted player to Answer. Once the game is
+// removed, nobody will, and the game is thrown away whatever happens in it,
+// so it is given any answer that lets the action finish.
+func (t *table) waitForAnswer(prompt game.Prompt) []string {
+	if t.isClosed() {
+		return anyAnswer(prompt)
+	}
+
+	t.promptMu.Lock()
+	t.prompted[prompt.Player] = true
+	t.promptMu.Unlock()
+
+	select {
+	case choice := <-t.answers:
+		return choice
+	case <-t.closed:
+		return anyAnswer(prompt)
+	}
+}
+
+// anyAnswer gives an answer that prompt will accept.
+func anyAnswer(prompt game.Prompt) []string {
+	switch prompt.Kind {
+	case game.ChooseRole:
+		return []string{role.King.String()}
+	case game.ChooseBoolean:
+		return []string{"false"}
+	}
+	return prompt.Choices[:prompt.Num]
+}
@@ -0,0 +1,169 @@
+package lobby
+
+import (
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+var players = []string{"alice", "bob", "carol", "dave"}
+
+// startTable starts a game in l whose every player will pass on a claim.
+func startTable(t *testing.T, l *Lobby) int {
+	id, err := l.Create([]string{"Witch", "King", "Queen", "Judge", "Bishop", "Thief"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range players {
+		if err := l.Join(id, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Start(id, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// claimWitch plays out the turns on which players must swap, and then has
+// the active player claim the Witch, which nobody challenges. It returns
+// once the Witch's power has been used, which waits for an Answer.
+func claimWitch(l *Lobby, id int) error {
+	for turn := uint(0); turn < game.OfficialRules().SwapTurns; turn++ {
+		err := l.Do(id, func(g *game.Game) error {
+			active := g.ActivePlayerName()
+			target := players[0]
+			if target == active {
+				target = players[1]
+			}
+			return g.SwapOrNotAs(active, target, false)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err := l.Do(id, func(g *game.Game) error {
+		return g.ClaimRoleAs(g.ActivePlayerName(), role.Witch.String())
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		claiming := false
+		err := l.Do(id, func(g *game.Game) error {
+			if g.View("").Claimant == "" {
+				return nil
+			}
+			claiming = true
+			return g.NoChallengeAs(g.ActivePlayerName())
+		})
+		if err != nil || !claiming {
+			return err
+		}
+	}
+}
+
+// waitForPrompt waits until the game is waiting for somebody to answer, and
+// tells who.
+func waitForPrompt(t *testing.T, l *Lobby, id int) string {
+	l.mu.Lock()
+	tab := l.tables[id]
+	l.mu.Unlock()
+
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		tab.promptMu.Lock()
+		for name := range tab.prompted {
+			tab.promptMu.Unlock()
+			return name
+		}
+		tab.promptMu.Unlock()
+	}
+	t.Fatalf("Game %d never asked anyone", id)
+	return ""
+}
+
+func TestRemoveWhilePrompting(t *testing.T) {
+	const tables = 5
+	const removed = 2
+
+	l := New()
+	ids := make([]int, tables)
+	for i := range ids {
+		ids[i] = startTable(t, l)
+	}
+
+	errs := make([]error, tables)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i, id int) {
+			defer wg.Done()
+			errs[i] = claimWitch(l, id)
+		}(i, id)
+	}
+
+	for i, id := range ids {
+		claimant := waitForPrompt(t, l, id)
+		if i == removed {
+			if err := l.Remove(id); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		victim := players[0]
+		if victim == claimant {
+			victim = players[1]
+		}
+		if err := l.Answer(id, claimant, []string{victim}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+
+	for i, id := range ids {
+		if i == removed {
+			if errs[i] != errClosed {
+				t.Errorf("Game %d: got %v, want %v", id, errs[i], errClosed)
+			}
+			if err := l.Do(id, func(*game.Game) error { return nil }); err == nil {
+				t.Errorf("Game %d can still be played after it was removed", id)
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Errorf("Game %d: %s", id, errs[i])
+		}
+	}
+
+	if got := len(l.List()); got != tables-1 {
+		t.Errorf("%d games left, want %d", got, tables-1)
+	}
+}
+
+func TestAnswerWithoutPrompt(t *testing.T) {
+	l := New()
+	id := startTable(t, l)
+	if err := l.Answer(id, players[0], []string{players[1]}); err == nil {
+		t.Errorf("%s answered without being asked", players[0])
+	}
+}
+
+func TestJoinTwice(t *testing.T) {
+	l := New()
+	id, err := l.Create([]string{"King", "Queen", "Judge", "Bishop", "Thief", "Witch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Join(id, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Join(id, "Alice"); err == nil {
+		t.Error("Alice joined alongside alice")
+	}
+}
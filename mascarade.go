@@ -1,90 +1,124 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 
+	"github.com/petertseng/mascarade/config"
 	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/sim"
 )
 
-func main() {
-	gameBuilder := game.NewBuilder()
+// A subcommand is one of the things mascarade can be asked to do, such as play a game.
+type subcommand struct {
+	name string
+	// What comes after the flags, for the usage message.
+	args    string
+	summary string
+	// flags defines the command's flags on fs, and gives back what to do with the rest of the arguments.
+	flags func(fs *flag.FlagSet) func(args []string) error
+}
 
-	if len(os.Args) == 1 {
-		fmt.Printf("usage: %s num_players player1 player2... playerN role1 role2... roleN\n", os.Args[0])
-		return
-	}
+var subcommands = []subcommand{
+	{"play", "num_players player1 player2... playerN <role1 role2... roleN | preset>", "Play a game on standard input and standard output", playFlags},
+	{"replay", "moves_file", "Play again a game recorded with play -record", replayFlags},
+	{"roles", "", "List the roles that can be played, with their powers", rolesFlags},
+	{"sim", "", "Play many games between bots, and report how they went", simFlags},
+}
 
-	numPlayers, err := strconv.ParseInt(os.Args[1], 0, 64)
-	if err != nil {
-		fmt.Println(err)
-		return
+func main() {
+	if len(os.Args) == 1 {
+		usage()
+		os.Exit(2)
 	}
 
-	if int64(len(os.Args)) < numPlayers+2 {
-		fmt.Printf("Expected %d player names, but only have %d\n", numPlayers, len(os.Args)-2)
-		return
+	name, args := os.Args[1], os.Args[2:]
+	// Before there were commands, a game was set up by the arguments alone.
+	if _, ok := findSubcommand(name); !ok && name != "" && (name[0] == '-' || name[0] >= '0' && name[0] <= '9') {
+		name, args = "play", os.Args[1:]
 	}
 
-	for i := 0; int64(i) < numPlayers; i++ {
-		gameBuilder.AddPlayer(os.Args[i+2])
+	c, ok := findSubcommand(name)
+	if !ok {
+		if name != "help" {
+			fmt.Fprintf(os.Stderr, "No such command %s\n", name)
+		}
+		usage()
+		os.Exit(2)
 	}
 
-	for _, role := range os.Args[numPlayers+2:] {
-		gameBuilder.AddRole(role)
+	fs := flag.NewFlagSet(c.name, flag.ExitOnError)
+	run := c.flags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s %s [flags] %s\n%s.\n", os.Args[0], c.name, c.args, c.summary)
+		fs.PrintDefaults()
 	}
+	fs.Parse(args)
 
-	game, err := gameBuilder.MakeGame(os.Stdout)
-	if err != nil {
+	if err := run(fs.Args()); err != nil {
 		fmt.Println(err)
-		return
+		os.Exit(1)
 	}
+}
 
-	input := bufio.NewReader(os.Stdin)
-	for len(game.Winners()) == 0 {
-		str, err := input.ReadString('\n')
-		if err != nil {
-			fmt.Println(err)
-			continue
+func findSubcommand(name string) (subcommand, bool) {
+	for _, c := range subcommands {
+		if c.name == name {
+			return c, true
 		}
+	}
+	return subcommand{}, false
+}
 
-		fields := strings.Fields(str)
-		if len(fields) == 0 {
-			continue
-		}
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [flags] [args]\n\ncommands:\n", os.Args[0])
+	for _, c := range subcommands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun %s <command> -h for a command's flags.\n", os.Args[0])
+}
 
-		switch strings.ToLower(fields[0]) {
-		case "swap":
-			if len(fields) >= 3 {
-				var actual bool
-				actual, err = strconv.ParseBool(fields[2])
-				if err == nil {
-					err = game.SwapOrNot(fields[1], actual)
-				}
-			} else {
-				err = fmt.Errorf("usage: swap <player_or_table> <actually_swap>")
-			}
-		case "peek":
-			err = game.Peek()
-		case "claim":
-			if len(fields) >= 2 {
-				err = game.ClaimRole(fields[1])
-			} else {
-				err = fmt.Errorf("usage: claim <role>")
+func rolesFlags(fs *flag.FlagSet) func(args []string) error {
+	configPath := fs.String("config", "", "JSON file whose rules to describe the powers by, in place of the official ones")
+
+	return func(args []string) error {
+		rules := game.OfficialRules()
+		if *configPath != "" {
+			c, err := config.Load(*configPath)
+			if err != nil {
+				return err
 			}
-		case "cc":
-			err = game.Challenge()
-		case "pass":
-			err = game.NoChallenge()
-		default:
-			err = fmt.Errorf("usage: <swap|peek|claim|cc|pass> [args]")
+			rules = c.Rules
 		}
+		for _, r := range game.PlayableRoles() {
+			fmt.Printf("%-14s %s\n", r, rules.PowerDescription(r))
+		}
+		fmt.Printf("\npresets: %s\n", strings.Join(game.Presets(), ", "))
+		return nil
+	}
+}
 
+func simFlags(fs *flag.FlagSet) func(args []string) error {
+	config := sim.Flags(fs)
+
+	return func(args []string) error {
+		c, err := config()
+		if err == sim.ErrNoRoles {
+			fs.Usage()
+			os.Exit(2)
+		}
+		if err != nil {
+			return err
+		}
+
+		stats, err := sim.Run(c)
 		if err != nil {
-			fmt.Println(err)
+			return err
 		}
+
+		fmt.Printf("Seed: %d\n", c.Seed)
+		return stats.Write(os.Stdout)
 	}
 }
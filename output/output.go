@@ -3,6 +3,7 @@ package output
 import (
 	"fmt"
 	"io"
+	"strings"
 )
 
 type Outputter interface {
@@ -24,3 +25,26 @@ func (out PrefixedOutputter) WritePublic(p []byte) (n int, err error) {
 func (out PrefixedOutputter) WritePrivate(name string, p []byte) (n int, err error) {
 	return out.output.Write(append([]byte(fmt.Sprintf("PRIVATE[%s]: ", name)), p...))
 }
+
+// NewLog makes an Outputter that keeps everything written publicly, line
+// by line, and throws away anything private.
+func NewLog() *Log {
+	return &Log{}
+}
+
+type Log struct {
+	lines []string
+}
+
+func (l *Log) WritePublic(p []byte) (n int, err error) {
+	l.lines = append(l.lines, strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
+func (l *Log) WritePrivate(name string, p []byte) (n int, err error) {
+	return len(p), nil
+}
+
+// Lines gives every public line written so far, oldest first.
+func (l *Log) Lines() []string {
+	return append([]string(nil), l.lines...)
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/petertseng/mascarade/bot"
+	"github.com/petertseng/mascarade/command"
+	"github.com/petertseng/mascarade/config"
+	"github.com/petertseng/mascarade/format"
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+func playFlags(fs *flag.FlagSet) func(args []string) error {
+	c := config.Config{Rules: game.OfficialRules(), Output: "text"}
+	configPath := fs.String("config", "", "JSON file declaring the players, roles, seed, rules and output, in place of arguments")
+	record := fs.String("record", "", "file to record the setup and every move in, for replay")
+	setupFlags(fs, &c)
+
+	return func(args []string) error {
+		if *configPath != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("Players and roles can't be given along with -config")
+			}
+			// Flags given on the command line win over the file.
+			given := make(map[string]string)
+			fs.Visit(func(f *flag.Flag) { given[f.Name] = f.Value.String() })
+			loaded, err := config.Load(*configPath)
+			if err != nil {
+				return err
+			}
+			c = loaded
+			for name, value := range given {
+				if err := fs.Set(name, value); err != nil {
+					return err
+				}
+			}
+		} else if err := fromArgs(&c, args); err != nil {
+			fs.Usage()
+			return err
+		}
+
+		if c.Seed == nil && *record != "" {
+			seed := time.Now().UnixNano()
+			c.Seed = &seed
+		}
+
+		s := &session{input: bufio.NewReader(os.Stdin)}
+		if *record != "" {
+			f, err := os.Create(*record)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if err := json.NewEncoder(f).Encode(c); err != nil {
+				return err
+			}
+			s.record = f
+		}
+		return s.play(c)
+	}
+}
+
+// setupFlags defines flags on fs for the seed, the output and the rules, which set them in c.
+func setupFlags(fs *flag.FlagSet, c *config.Config) {
+	fs.Var(seedValue{&c.Seed}, "seed", "the seating and the deal depend only on this `int`, to repeat a game (default random)")
+	fs.StringVar(&c.Output, "output", c.Output, "text, or json for one JSON object per event")
+
+	r := &c.Rules
+	fs.Uint64Var(&r.StartingCoins, "starting-coins", r.StartingCoins, "coins each player starts with")
+	fs.Uint64Var(&r.WinningCoins, "winning-coins", r.WinningCoins, "coins to win with")
+	fs.BoolVar(&r.BrokeEndsGame, "broke-ends-game", r.BrokeEndsGame, "whether the game ends, won by the richest, as soon as somebody has no coins")
+	fs.Uint64Var(&r.CheatCoins, "cheat-coins", r.CheatCoins, "coins the Cheat wins with")
+	fs.Uint64Var(&r.WidowCoins, "widow-coins", r.WidowCoins, "coins the Widow takes up to")
+	fs.UintVar(&r.SwapTurns, "swap-turns", r.SwapTurns, "turns at the start when players may only swap (or not)")
+	fs.Uint64Var(&r.Fine, "fine", r.Fine, "what a false claim costs")
+	fs.BoolVar(&r.OpeningReveal, "opening-reveal", r.OpeningReveal, "whether every card is shown to everyone before the first turn")
+}
+
+// seedValue is a flag setting a config's seed, which is otherwise left to chance.
+type seedValue struct {
+	seed **int64
+}
+
+func (v seedValue) String() string {
+	if v.seed == nil || *v.seed == nil {
+		return ""
+	}
+	return strconv.FormatInt(**v.seed, 10)
+}
+
+func (v seedValue) Set(s string) error {
+	seed, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		return err
+	}
+	*v.seed = &seed
+	return nil
+}
+
+// fromArgs sets up c from the command line: how many players, their names,
+// and then the roles or a preset.
+func fromArgs(c *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Expected the number of players")
+	}
+
+	numPlayers, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+	if numPlayers < 0 || len(args) < numPlayers+1 {
+		return fmt.Errorf("Expected %d player names, but only have %d", numPlayers, len(args)-1)
+	}
+	c.Players = args[1 : numPlayers+1]
+
+	roles := args[numPlayers+1:]
+	if len(roles) == 1 {
+		for _, preset := range game.Presets() {
+			if roles[0] == preset {
+				c.Preset = preset
+				return nil
+			}
+		}
+	}
+	c.Roles = make(map[string]int)
+	for _, name := range roles {
+		r, err := role.FromString(name)
+		if err != nil {
+			return err
+		}
+		c.Roles[r.String()] = r.Copies()
+	}
+	return nil
+}
+
+func replayFlags(fs *flag.FlagSet) func(args []string) error {
+	output := fs.String("output", "", "text, or json for one JSON object per event (default as recorded)")
+
+	return func(args []string) error {
+		if len(args) != 1 {
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		s := &session{input: bufio.NewReader(f)}
+		setup, ok := s.readLine()
+		if !ok {
+			return fmt.Errorf("%s: nothing recorded", args[0])
+		}
+		c, err := config.Parse(strings.NewReader(setup))
+		if err != nil {
+			return fmt.Errorf("%s: %s", args[0], err)
+		}
+		if *output != "" {
+			c.Output = *output
+		}
+		// Moves would get in the way of reading the output as JSON.
+		s.echo = c.Output != "json"
+		return s.play(c)
+	}
+}
+
+// A session plays one game, reading every move and every answer to a power's prompt from input.
+type session struct {
+	input *bufio.Reader
+	// If not nil, every line read is written to it.
+	record io.Writer
+	// Whether to print every line read, as when it is not being typed.
+	echo bool
+
+	game    *game.Game
+	advisor *bot.Advisor
+	// What the players are told through, apart from the game.
+	format format.Formatter
+}
+
+// readLine gives the next line of input that isn't blank, or false if there are no more.
+func (s *session) readLine() (string, bool) {
+	for {
+		str, err := s.input.ReadString('\n')
+		str = strings.TrimSpace(str)
+		if str != "" {
+			if s.record != nil {
+				fmt.Fprintln(s.record, str)
+			}
+			if s.echo {
+				fmt.Printf("> %s\n", str)
+			}
+			return str, true
+		}
+		if err != nil {
+			return "", false
+		}
+	}
+}
+
+func (s *session) choose(prompt game.Prompt) []string {
+	for {
+		str, ok := s.readLine()
+		if !ok {
+			fmt.Printf("Input ended while %s was choosing for the %s\n", prompt.Player, prompt.Power)
+			os.Exit(1)
+		}
+		answer, err := command.ParseAnswer(prompt, str)
+		if err == nil {
+			s.advisor.Answer(prompt, answer)
+			return answer
+		}
+		fmt.Println(err)
+	}
+}
+
+func (s *session) play(c config.Config) error {
+	gameBuilder, err := c.Builder()
+	if err != nil {
+		return err
+	}
+	f, err := c.Formatter(os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	s.format = f
+	gameBuilder.SetChoiceGetter(s.choose)
+	s.advisor = bot.NewAdvisor(rand.New(rand.NewSource(time.Now().UnixNano())), c.Players)
+	g, err := gameBuilder.MakeGameWithFormatter(s.advisor.Formatter(f))
+	if err != nil {
+		return err
+	}
+	s.game = &g
+
+	for len(s.game.Winners()) == 0 {
+		str, ok := s.readLine()
+		if !ok {
+			return nil
+		}
+		if err := s.do(str); err != nil {
+			fmt.Println(err)
+		}
+	}
+	return nil
+}
+
+// tableCommands are what may be typed at any time without taking a turn,
+// with what they do.
+var tableCommands = [][2]string{
+	{"status", "show whose turn it is, everyone's coins, and the courthouse"},
+	{"history", "show everything that has happened so far"},
+	{"roles", "show the roles in the game, with their powers"},
+	{"hint", "suggest a move to whoever must act"},
+	{"help", "show this"},
+}
+
+// do carries out one line typed by a player.
+func (s *session) do(line string) error {
+	switch strings.ToLower(line) {
+	case "status":
+		s.status()
+		return nil
+	case "history":
+		s.history()
+		return nil
+	case "roles":
+		s.roles()
+		return nil
+	case "hint":
+		s.hint()
+		return nil
+	case "help":
+		s.help()
+		return nil
+	}
+
+	action, err := command.Parse(line)
+	var usage *command.UsageError
+	if errors.As(err, &usage) && usage.Command == "" {
+		return fmt.Errorf("usage: %s, or help", command.Usage)
+	}
+	if err != nil {
+		return err
+	}
+	if action.Kind == game.SwapAction {
+		return s.advisor.SwapOrNot(s.game, action.Target, action.ActuallySwap)
+	}
+	return s.game.Perform(action)
+}
+
+func (s *session) status() {
+	v := s.game.View("")
+	if v.Claimant != "" {
+		fmt.Printf("Turn %d: %s claims to be the %s, and %s must challenge or pass.\n", v.TurnCount+1, v.Claimant, v.ClaimedRole, v.Active)
+	} else {
+		fmt.Printf("Turn %d: it's %s's turn.\n", s.game.TurnCount()+1, s.game.ActivePlayerName())
+	}
+
+	for _, p := range v.Players {
+		coins, err := s.game.Coins(p.Name)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		revealed := ""
+		if p.Revealed {
+			revealed = fmt.Sprintf(", last revealed on turn %d", p.LastRevealed+1)
+		}
+		fmt.Printf("  %s: %d coins%s\n", p.Name, coins, revealed)
+	}
+	if len(v.TableCards) > 0 {
+		fmt.Printf("Table cards: %s\n", strings.Join(v.TableCards, ", "))
+	}
+	fmt.Printf("Courthouse: %d coins\n", s.game.Courthouse())
+}
+
+func (s *session) history() {
+	for _, line := range s.game.History() {
+		fmt.Println(line)
+	}
+}
+
+func (s *session) roles() {
+	deck := s.game.Deck()
+	v := s.game.View("")
+	for _, r := range v.Roles {
+		copies := ""
+		if deck[r] > 1 {
+			copies = fmt.Sprintf(" (%d cards)", deck[r])
+		}
+		fmt.Printf("%s%s: %s\n", r, copies, v.Rules.PowerDescription(r))
+	}
+}
+
+// hint advises whoever must act, privately, since the advice comes of what they alone have seen.
+func (s *session) hint() {
+	active := s.game.ActivePlayerName()
+	advice, err := s.advisor.Advise(s.game.View(active))
+	if err != nil {
+		s.format.Error(active, err)
+		return
+	}
+	s.format.Hint(active, advice.String())
+}
+
+func (s *session) help() {
+	fmt.Println("Moves, made by whoever must act:")
+	for _, usage := range command.Usages() {
+		fmt.Printf("  %s\n", usage)
+	}
+	fmt.Println("At any time, without taking a turn:")
+	for _, c := range tableCommands {
+		fmt.Printf("  %-8s %s\n", c[0], c[1])
+	}
+}
@@ -15,6 +15,7 @@ type Player struct {
 	coins uint64
 
 	roleOwner
+	revealed         bool
 	lastRevealedTurn uint
 }
 
@@ -30,15 +31,27 @@ func (tc TableCard) Name() string {
 	return fmt.Sprintf("Table Card %d", tc.id)
 }
 
-func New(name string, role role.Role) Player {
-	return Player{name: name, coins: 6, roleOwner: roleOwner{role: role}}
+func New(name string, role role.Role, coins uint64) Player {
+	return Player{name: name, coins: coins, roleOwner: roleOwner{role: role}}
+}
+
+// Clone copies the player, dealing the copy r in place of their card.
+func (p Player) Clone(r role.Role) *Player {
+	p.role = r
+	return &p
+}
+
+// Clone copies the table card, making the copy r.
+func (tc TableCard) Clone(r role.Role) *TableCard {
+	tc.role = r
+	return &tc
 }
 
 type CoinOwner interface {
 	Name() string
 	Coins() uint64
 	AddCoins(uint64)
-	PayFine()
+	PayFine(fine uint64) uint64
 	Pay(CoinOwner, uint64) uint64
 }
 
@@ -74,13 +87,29 @@ func (p *Player) AddCoins(coins uint64) {
 	p.coins += coins
 }
 
-func (p *Player) PayFine() {
-	p.coins--
+// PayFine takes a fine from the player, or all they have if it's less, and gives how much was paid.
+func (p *Player) PayFine(fine uint64) uint64 {
+	if p.coins < fine {
+		fine = p.coins
+	}
+	p.coins -= fine
+	return fine
 }
 
 func (p *Player) Reveal(turn uint) {
+	p.revealed = true
 	p.lastRevealedTurn = turn
 }
+
+// Revealed tells whether the player's card has ever been revealed.
+func (p Player) Revealed() bool {
+	return p.revealed
+}
+
+// RevealedOn tells whether the player's card was last revealed on turn.
+func (p Player) RevealedOn(turn uint) bool {
+	return p.revealed && p.lastRevealedTurn == turn
+}
 func (p Player) LastRevealed() uint {
 	return p.lastRevealedTurn
 }
@@ -2,7 +2,9 @@ package role
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"unicode"
 )
 
 type Role int
@@ -37,23 +39,176 @@ const (
 	Usurper
 )
 
+// ids maps each role's name, folded, to the role.
 var ids = map[string]Role{}
-var idsInitialized = false
 
+// all is every role, in order.
+var all []Role
+
+// ids is filled in eagerly so that FromString is safe to call from many goroutines.
+func init() {
+	for id, nameAndPower := range namesAndPowers {
+		ids[fold(nameAndPower[0])] = id
+		all = append(all, id)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+}
+
+// FromString finds the role a player means by s. Case, accents, spaces and
+// hyphens don't matter, so "puppet master" and "Puppet-Master" are both the
+// Puppet Master, and neither does the end of a name if no other role begins
+// the same way: "puppet" will do too. When no role is found, the error is a
+// *NameError suggesting the roles that might have been meant.
 func FromString(s string) (Role, error) {
-	if !idsInitialized {
-		for id, nameAndPower := range namesAndPowers {
-			ids[strings.ToLower(nameAndPower[0])] = id
+	folded := fold(s)
+	if role, ok := ids[folded]; ok {
+		return role, nil
+	}
+	if folded == "" {
+		return NoSuchRole, &NameError{Name: s}
+	}
+
+	var prefixed []Role
+	for _, r := range all {
+		if strings.HasPrefix(fold(r.String()), folded) {
+			prefixed = append(prefixed, r)
 		}
-		idsInitialized = true
+	}
+	if len(prefixed) == 1 {
+		return prefixed[0], nil
+	}
+	if len(prefixed) > 1 {
+		return NoSuchRole, &NameError{Name: s, Suggestions: prefixed, Ambiguous: true}
 	}
 
-	role, ok := ids[strings.ToLower(s)]
-	if ok {
-		return role, nil
+	// Suggest the roles spelled most nearly like s, if any are near enough
+	// that s is more likely a typo than something else entirely.
+	best := maxTypos + 1
+	var near []Role
+	for _, r := range all {
+		d := distance(folded, fold(r.String()))
+		if d > maxTypos || d >= len([]rune(folded)) {
+			continue
+		}
+		if d < best {
+			best = d
+			near = nil
+		}
+		if d == best {
+			near = append(near, r)
+		}
+	}
+	return NoSuchRole, &NameError{Name: s, Suggestions: near}
+}
+
+// FromName finds the role named exactly s, as its String gives it, for
+// names read by programs rather than typed by players. When s is no role's
+// name, the error is a *NameError suggesting the role FromString would
+// take it for, if any.
+func FromName(s string) (Role, error) {
+	for _, r := range all {
+		if r.String() == s {
+			return r, nil
+		}
+	}
+	r, err := FromString(s)
+	if err != nil {
+		return NoSuchRole, err
+	}
+	return NoSuchRole, &NameError{Name: s, Suggestions: []Role{r}}
+}
+
+// The most letters a name can have wrong, missing, or extra, and still be suggested.
+const maxTypos = 2
+
+// A NameError says that a name is no role, or could be any of several.
+type NameError struct {
+	Name string
+	// The roles Name begins, if Ambiguous, or else those spelled nearly like it.
+	Suggestions []Role
+	Ambiguous   bool
+}
+
+func (e *NameError) Error() string {
+	if e.Ambiguous {
+		return fmt.Sprintf("%s could be any of %s", e.Name, list(e.Suggestions))
+	}
+	if len(e.Suggestions) > 0 {
+		return fmt.Sprintf("No such role %s; did you mean %s?", e.Name, list(e.Suggestions))
+	}
+	return fmt.Sprintf("No such role %s", e.Name)
+}
+
+// list names roles the way they would be said, as in "King, Queen or Judge".
+func list(roles []Role) string {
+	names := make([]string, len(roles))
+	for i, r := range roles {
+		names[i] = r.String()
+	}
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// accents lists the accented letters that are taken for each plain one.
+var accents = map[rune]string{
+	'a': "àáâãäåāă",
+	'c': "çćč",
+	'e': "èéêëēė",
+	'i': "ìíîïī",
+	'n': "ñń",
+	'o': "òóôõöøō",
+	'u': "ùúûüū",
+	'y': "ýÿ",
+}
+
+// unaccented maps each accented letter to the plain one it is taken for.
+var unaccented = func() map[rune]rune {
+	m := make(map[rune]rune)
+	for plain, accented := range accents {
+		for _, c := range accented {
+			m[c] = plain
+		}
 	}
+	return m
+}()
+
+// fold puts a name in the form names are compared in: lowercase, unaccented,
+// and with only letters and digits.
+func fold(s string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(s) {
+		if plain, ok := unaccented[c]; ok {
+			c = plain
+		}
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
 
-	return NoSuchRole, fmt.Errorf("No such role %s", s)
+// distance is how many letters must be changed, added or removed to turn a into b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
 }
 
 var namesAndPowers = map[Role][2]string{
@@ -92,6 +247,8 @@ func (r Role) String() string {
 	return fmt.Sprintf("Unknown role ID %d", r)
 }
 
+// PowerDescription describes what r does by the official rules;
+// game.Rules.PowerDescription describes it by any others.
 func (r Role) PowerDescription() string {
 	nameAndPower, ok := namesAndPowers[r]
 	if ok {
@@ -101,10 +258,42 @@ func (r Role) PowerDescription() string {
 	return fmt.Sprintf("Power for Unknown role ID %d", r)
 }
 
+// Pair tells whether the role comes in pairs, as the Peasants do.
 func (r Role) Pair() bool {
 	return r == Peasant
 }
 
+// Copies is how many cards of the role a game has unless told otherwise.
+func (r Role) Copies() int {
+	if r.Pair() {
+		return 2
+	}
+	return 1
+}
+
+// Base tells whether the role is in the base game rather than the expansion.
+func (r Role) Base() bool {
+	return r >= Judge && r < Alchemist
+}
+
 func (r Role) CanAnnounce() bool {
 	return r != Damned
 }
+
+// MarshalText writes a role as its name, so that roles read well in JSON.
+func (r Role) MarshalText() ([]byte, error) {
+	if _, ok := namesAndPowers[r]; !ok {
+		return nil, fmt.Errorf("Unknown role ID %d", r)
+	}
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText reads a role by its exact name, as MarshalText writes it.
+func (r *Role) UnmarshalText(text []byte) error {
+	role, err := FromName(string(text))
+	if err != nil {
+		return err
+	}
+	*r = role
+	return nil
+}
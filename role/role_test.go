@@ -0,0 +1,75 @@
+package role
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFromString(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Role
+		// The roles suggested, if s is no role.
+		suggestions []Role
+		ambiguous   bool
+	}{
+		{s: "King", want: King},
+		{s: "king", want: King},
+		{s: "  QUEEN ", want: Queen},
+		{s: "Puppet Master", want: PuppetMaster},
+		{s: "puppet-master", want: PuppetMaster},
+		{s: "puppetmaster", want: PuppetMaster},
+		{s: "puppet", want: PuppetMaster},
+		{s: "Inq", want: Inquisitor},
+		{s: "wídow", want: Widow},
+		{s: "p", suggestions: []Role{Peasant, PuppetMaster, Patron, Princess}, ambiguous: true},
+		{s: "kinng", suggestions: []Role{King}},
+		{s: "Thieff", suggestions: []Role{Thief}},
+		{s: "xyzzy"},
+		{s: ""},
+	}
+
+	for _, test := range tests {
+		got, err := FromString(test.s)
+		if test.want != NoSuchRole {
+			if err != nil || got != test.want {
+				t.Errorf("FromString(%q) = %s, %v; want %s", test.s, got, err, test.want)
+			}
+			continue
+		}
+
+		var nameErr *NameError
+		if !errors.As(err, &nameErr) {
+			t.Errorf("FromString(%q) = %s, %v; want a NameError", test.s, got, err)
+			continue
+		}
+		if !reflect.DeepEqual(nameErr.Suggestions, test.suggestions) || nameErr.Ambiguous != test.ambiguous {
+			t.Errorf("FromString(%q) suggested %v (ambiguous %t), want %v (ambiguous %t)", test.s, nameErr.Suggestions, nameErr.Ambiguous, test.suggestions, test.ambiguous)
+		}
+	}
+}
+
+func TestFromName(t *testing.T) {
+	for _, r := range all {
+		if got, err := FromName(r.String()); err != nil || got != r {
+			t.Errorf("FromName(%q) = %s, %v; want %s", r.String(), got, err, r)
+		}
+	}
+
+	for _, s := range []string{"king", "Puppet", "Kinng", ""} {
+		if got, err := FromName(s); err == nil {
+			t.Errorf("FromName(%q) = %s, want an error", s, got)
+		}
+	}
+}
+
+func TestUnmarshalText(t *testing.T) {
+	var r Role
+	if err := r.UnmarshalText([]byte("Puppet Master")); err != nil || r != PuppetMaster {
+		t.Errorf("Got %s, %v; want %s", r, err, PuppetMaster)
+	}
+	if err := r.UnmarshalText([]byte("puppet")); err == nil {
+		t.Errorf("Read puppet as %s, want an error", r)
+	}
+}
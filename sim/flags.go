@@ -0,0 +1,45 @@
+package sim
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/petertseng/mascarade/bot"
+)
+
+// ErrNoRoles says that a simulation was asked for without -roles.
+var ErrNoRoles = errors.New("-roles is required")
+
+// Flags defines on fs the flags that say which games to simulate. Once fs
+// is parsed, the function it gives back registers the extra bots named by
+// -policy and -personalities, and gives the Config.
+func Flags(fs *flag.FlagSet) func() (Config, error) {
+	players := fs.Int("players", 4, "number of players")
+	roles := fs.String("roles", "", "comma-separated roles in the game, or a preset (required)")
+	bots := fs.String("bots", "knowing", fmt.Sprintf("comma-separated bots for each player in turn, from %s", strings.Join(bot.Kinds(), ", ")))
+	games := fs.Int("games", 1000, "number of games to play")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "seed, to repeat a simulation exactly")
+	workers := fs.Int("workers", 0, "games to play at once (default one per CPU)")
+	policy := fs.String("policy", "", "policy file from mascarade-train, to enter the \"trained\" bot")
+	personalities := fs.String("personalities", "", "profile file of personalities, each entering a bot by its name")
+
+	return func() (Config, error) {
+		if *roles == "" {
+			return Config{}, ErrNoRoles
+		}
+		if err := bot.LoadExtras(*policy, *personalities); err != nil {
+			return Config{}, err
+		}
+		return Config{
+			Roles:   strings.Split(*roles, ","),
+			Players: *players,
+			Bots:    strings.Split(*bots, ","),
+			Games:   *games,
+			Seed:    *seed,
+			Workers: *workers,
+		}, nil
+	}
+}
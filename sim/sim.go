@@ -0,0 +1,291 @@
+package sim
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/petertseng/mascarade/bot"
+	"github.com/petertseng/mascarade/format"
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/role"
+)
+
+// Config says which games to simulate.
+type Config struct {
+	// The roles in every game, or the name of a preset alone to choose them
+	// for each game as GameBuilder.UsePreset does.
+	Roles   []string
+	Players int
+	// The kind of bot for each player, as understood by bot.New. If there
+	// are fewer kinds than players, they are used over again in order.
+	Bots  []string
+	Games int
+	// Every game is dealt and played the same way each time a seed is used.
+	Seed int64
+	// How many games to play at once. If zero, one per CPU.
+	Workers int
+}
+
+// Stats is what was learned from a number of games.
+// When several players win a game, they share that one win between them.
+type Stats struct {
+	Games int
+	Turns uint
+
+	// By seating position; the first player is at 0.
+	SeatWins []float64
+
+	// By the role each player held when the game ended.
+	RoleWins map[role.Role]float64
+	RoleHeld map[role.Role]int
+
+	// By the kind of bot.
+	BotWins  map[string]float64
+	BotSeats map[string]int
+
+	Conditions map[game.WinCondition]int
+	// How many coins were left in the courthouse when the game ended.
+	Courthouses map[uint64]int
+}
+
+func newStats(players int) Stats {
+	return Stats{
+		SeatWins:    make([]float64, players),
+		RoleWins:    make(map[role.Role]float64),
+		RoleHeld:    make(map[role.Role]int),
+		BotWins:     make(map[string]float64),
+		BotSeats:    make(map[string]int),
+		Conditions:  make(map[game.WinCondition]int),
+		Courthouses: make(map[uint64]int),
+	}
+}
+
+// Run plays out every game in c silently and gathers up the results.
+func Run(c Config) (Stats, error) {
+	if c.Players < 1 {
+		return Stats{}, fmt.Errorf("Need at least one player, not %d", c.Players)
+	}
+	if len(c.Bots) == 0 {
+		return Stats{}, fmt.Errorf("Need at least one kind of bot")
+	}
+
+	workers := c.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	stats := newStats(c.Players)
+	var mu sync.Mutex
+	var firstErr error
+
+	games := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range games {
+				err := play(c, i, &stats, &mu)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < c.Games; i++ {
+		games <- i
+	}
+	close(games)
+	wg.Wait()
+
+	return stats, firstErr
+}
+
+// A Result is how one game went.
+type Result struct {
+	Game *game.Game
+	// The kind of bot that played as each player, by name.
+	Bots map[string]string
+}
+
+// Play plays one game silently, between bots of the given kinds, one per player.
+// The players are named P1, P2, P3... in the order of bots. Roles may be
+// the name of a preset alone, as in Config.
+func Play(roles []string, bots []string, seed int64) (Result, error) {
+	rng := rand.New(rand.NewSource(seed))
+
+	gameBuilder := game.NewBuilder()
+	gameBuilder.SetSeed(rng.Int63())
+	gameBuilder.SetHistory(false)
+	if len(roles) == 1 && isPreset(roles[0]) {
+		if err := gameBuilder.UsePreset(roles[0]); err != nil {
+			return Result{}, err
+		}
+	} else {
+		for _, name := range roles {
+			if err := gameBuilder.AddRole(name); err != nil {
+				return Result{}, err
+			}
+		}
+	}
+
+	seats := make(map[string]bot.Bot)
+	kinds := make(map[string]string)
+	for i, kind := range bots {
+		name := fmt.Sprintf("P%d", i+1)
+		b, err := bot.New(kind, rng)
+		if err != nil {
+			return Result{}, err
+		}
+		gameBuilder.AddPlayer(name)
+		seats[name] = b
+		kinds[name] = kind
+	}
+
+	table := bot.NewTable(rng, seats)
+	gameBuilder.SetChoiceGetter(table.Choose)
+	g, err := gameBuilder.MakeGameWithFormatter(table.Formatter(format.NewNop()))
+	if err != nil {
+		return Result{}, err
+	}
+	if err := table.Play(&g); err != nil {
+		return Result{}, err
+	}
+
+	return Result{Game: &g, Bots: kinds}, nil
+}
+
+func isPreset(name string) bool {
+	for _, preset := range game.Presets() {
+		if name == preset {
+			return true
+		}
+	}
+	return false
+}
+
+// play plays the game numbered i, adding its results to stats under mu.
+func play(c Config, i int, stats *Stats, mu *sync.Mutex) error {
+	bots := make([]string, c.Players)
+	for p := range bots {
+		bots[p] = c.Bots[p%len(c.Bots)]
+	}
+
+	result, err := Play(c.Roles, bots, c.Seed+int64(i))
+	if err != nil {
+		return fmt.Errorf("Game %d: %s", i, err)
+	}
+
+	g := result.Game
+	v := g.View("")
+	winners := g.Winners()
+	share := 1 / float64(len(winners))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	stats.Games++
+	// The turn on which the game was won is not counted in TurnCount.
+	stats.Turns += v.TurnCount + 1
+	stats.Conditions[g.WinCondition()]++
+	stats.Courthouses[v.Courthouse]++
+
+	for seat, p := range v.Players {
+		held, err := g.Card(p.Name)
+		if err != nil {
+			return err
+		}
+		kind := result.Bots[p.Name]
+		stats.RoleHeld[held]++
+		stats.BotSeats[kind]++
+
+		for _, winner := range winners {
+			if winner == p.Name {
+				stats.SeatWins[seat] += share
+				stats.RoleWins[held] += share
+				stats.BotWins[kind] += share
+			}
+		}
+	}
+
+	return nil
+}
+
+func percent(part, whole float64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return 100 * part / whole
+}
+
+// Write reports the stats in a form for people to read.
+func (s Stats) Write(w io.Writer) error {
+	if s.Games == 0 {
+		_, err := fmt.Fprintln(w, "No games were played.")
+		return err
+	}
+	games := float64(s.Games)
+
+	lines := []string{
+		fmt.Sprintf("Games: %d", s.Games),
+		fmt.Sprintf("Average length: %.2f turns", float64(s.Turns)/games),
+		"",
+		"Won by:",
+	}
+	for _, wc := range []game.WinCondition{game.WinTarget, game.WinBroke, game.WinCheat} {
+		lines = append(lines, fmt.Sprintf("  %-9s %6d (%5.1f%%)", wc, s.Conditions[wc], percent(float64(s.Conditions[wc]), games)))
+	}
+
+	lines = append(lines, "", "Win rate by seat:")
+	for seat, wins := range s.SeatWins {
+		lines = append(lines, fmt.Sprintf("  %-13d %5.1f%%", seat+1, percent(wins, games)))
+	}
+
+	roles := make([]role.Role, 0, len(s.RoleHeld))
+	for r := range s.RoleHeld {
+		roles = append(roles, r)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+	lines = append(lines, "", "Win rate by role held at the end:")
+	for _, r := range roles {
+		lines = append(lines, fmt.Sprintf("  %-13s %5.1f%% of %d", r, percent(s.RoleWins[r], float64(s.RoleHeld[r])), s.RoleHeld[r]))
+	}
+
+	kinds := make([]string, 0, len(s.BotSeats))
+	for kind := range s.BotSeats {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	lines = append(lines, "", "Win rate by bot:")
+	for _, kind := range kinds {
+		lines = append(lines, fmt.Sprintf("  %-13s %5.1f%% of %d", kind, percent(s.BotWins[kind], float64(s.BotSeats[kind])), s.BotSeats[kind]))
+	}
+
+	sizes := make([]uint64, 0, len(s.Courthouses))
+	total := uint64(0)
+	for size, count := range s.Courthouses {
+		sizes = append(sizes, size)
+		total += size * uint64(count)
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+	lines = append(lines, "", fmt.Sprintf("Courthouse at the end (average %.2f coins):", float64(total)/games))
+	for _, size := range sizes {
+		lines = append(lines, fmt.Sprintf("  %-13d %6d (%5.1f%%)", size, s.Courthouses[size], percent(float64(s.Courthouses[size]), games)))
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package tournament
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/petertseng/mascarade/game"
+	"github.com/petertseng/mascarade/sim"
+)
+
+// Every bot starts with this rating.
+const initialRating = 1500
+
+// Config says which bots play in a tournament, and how much.
+type Config struct {
+	// The kinds of bot taking part, as understood by bot.New.
+	Bots []string
+	// Each game has between MinPlayers and MaxPlayers players.
+	MinPlayers int
+	MaxPlayers int
+	Games      int
+	// The most a rating can change by in one game.
+	K    float64
+	Seed int64
+	// How many games to play at once. If zero, one per CPU.
+	Workers int
+}
+
+// A Standing is how well one bot did over a tournament.
+// A game won by several players counts as a share of a win for each.
+type Standing struct {
+	Bot    string
+	Rating float64
+	Games  int
+	Wins   float64
+}
+
+// A setup is everything that was chosen at random for one game.
+type setup struct {
+	roles []string
+	bots  []string
+}
+
+// An outcome is all that is kept of a game once it is played, so that
+// a long tournament doesn't hold on to every game.
+type outcome struct {
+	winners []string
+	// The kind of bot that played as each player, by name.
+	bots map[string]string
+}
+
+// Run plays every game of a tournament, and returns the standings from best rated to worst.
+// Seating and roles are chosen at random for every game. Games are played
+// at the same time but rated one after another, so a seed gives the same standings every time.
+func Run(c Config) ([]Standing, error) {
+	if len(c.Bots) == 0 {
+		return nil, fmt.Errorf("Need at least one kind of bot")
+	}
+	if c.MinPlayers < game.MinPlayers || c.MaxPlayers > game.MaxPlayers || c.MaxPlayers < c.MinPlayers {
+		return nil, fmt.Errorf("Can't have between %d and %d players", c.MinPlayers, c.MaxPlayers)
+	}
+
+	workers := c.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	rng := rand.New(rand.NewSource(c.Seed))
+	setups := make([]setup, c.Games)
+	for i := range setups {
+		setups[i] = c.randomSetup(rng)
+	}
+
+	outcomes := make([]outcome, c.Games)
+	errs := make([]error, c.Games)
+	games := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range games {
+				result, err := sim.Play(setups[i].roles, setups[i].bots, c.Seed+int64(i))
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				outcomes[i] = outcome{winners: result.Game.Winners(), bots: result.Bots}
+			}
+		}()
+	}
+	for i := range setups {
+		games <- i
+	}
+	close(games)
+	wg.Wait()
+
+	standings := make(map[string]*Standing)
+	for _, kind := range c.Bots {
+		standings[kind] = &Standing{Bot: kind, Rating: initialRating}
+	}
+	for i, o := range outcomes {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("Game %d: %s", i, errs[i])
+		}
+		rate(standings, o, c.K)
+	}
+
+	sorted := make([]Standing, 0, len(standings))
+	for _, s := range standings {
+		sorted = append(sorted, *s)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Rating != sorted[j].Rating {
+			return sorted[i].Rating > sorted[j].Rating
+		}
+		return sorted[i].Bot < sorted[j].Bot
+	})
+	return sorted, nil
+}
+
+// randomSetup seats bots at random. The roles are left to the "random" preset,
+// which chooses them as each game is made.
+func (c Config) randomSetup(rng *rand.Rand) setup {
+	players := c.MinPlayers + rng.Intn(c.MaxPlayers-c.MinPlayers+1)
+
+	// Every bot gets a seat before any gets two.
+	bots := make([]string, 0, players)
+	for len(bots) < players {
+		for _, i := range rng.Perm(len(c.Bots)) {
+			if len(bots) < players {
+				bots = append(bots, c.Bots[i])
+			}
+		}
+	}
+
+	return setup{roles: []string{"random"}, bots: bots}
+}
+
+// rate updates ratings after a game, counting it as a match between every
+// two players of different kinds: winning is worth 1, losing 0, and winning
+// or losing together 1/2.
+func rate(standings map[string]*Standing, o outcome, k float64) {
+	won := make(map[string]bool)
+	for _, winner := range o.winners {
+		won[winner] = true
+	}
+
+	before := make(map[string]float64)
+	for _, kind := range o.bots {
+		before[kind] = standings[kind].Rating
+	}
+
+	players := make([]string, 0, len(o.bots))
+	for name := range o.bots {
+		players = append(players, name)
+	}
+	sort.Strings(players)
+
+	for _, p := range players {
+		kind := o.bots[p]
+		standings[kind].Games++
+		if won[p] {
+			standings[kind].Wins += 1 / float64(len(won))
+		}
+
+		for _, q := range players {
+			otherKind := o.bots[q]
+			if otherKind == kind {
+				continue
+			}
+			score := 0.5
+			if won[p] && !won[q] {
+				score = 1
+			} else if won[q] && !won[p] {
+				score = 0
+			}
+			expected := 1 / (1 + math.Pow(10, (before[otherKind]-before[kind])/400))
+			standings[kind].Rating += k / float64(len(players)-1) * (score - expected)
+		}
+	}
+}
+
+// WriteLeaderboard writes out standings as a table for people to read.
+func WriteLeaderboard(w io.Writer, standings []Standing) error {
+	if _, err := fmt.Fprintf(w, "%-4s %-16s %7s %7s %6s\n", "Rank", "Bot", "Rating", "Games", "Won"); err != nil {
+		return err
+	}
+	for i, s := range standings {
+		won := 0.0
+		if s.Games > 0 {
+			won = 100 * s.Wins / float64(s.Games)
+		}
+		if _, err := fmt.Fprintf(w, "%-4d %-16s %7.1f %7d %5.1f%%\n", i+1, s.Bot, s.Rating, s.Games, won); err != nil {
+			return err
+		}
+	}
+	return nil
+}